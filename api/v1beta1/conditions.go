@@ -0,0 +1,252 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+)
+
+// Octavia Condition Types used by API objects.
+const (
+	// OctaviaAPIReadyCondition Status=True condition which indicates if the OctaviaAPI is configured and operational
+	OctaviaAPIReadyCondition condition.Type = "OctaviaAPIReady"
+
+	// FlavorProfileReadyCondition Status=True condition which indicates if the flavor profile got created/updated in Octavia
+	FlavorProfileReadyCondition condition.Type = "FlavorProfileReady"
+
+	// FlavorReadyCondition Status=True condition which indicates if the flavor got created/updated in Octavia
+	FlavorReadyCondition condition.Type = "FlavorReady"
+
+	// OVNDBReadyCondition Status=True condition which indicates if the OVN NB and SB DB endpoints got discovered
+	OVNDBReadyCondition condition.Type = "OVNDBReady"
+
+	// MemcachedReadyCondition Status=True condition which indicates if the servers of the Memcached instance got discovered
+	MemcachedReadyCondition condition.Type = "MemcachedReady"
+
+	// ApplicationCredentialReadyCondition Status=True condition which indicates if the application credential of the service user got created
+	ApplicationCredentialReadyCondition condition.Type = "ApplicationCredentialReady"
+
+	// ReconcilePausedCondition Status=True condition which indicates that the reconcile is paused
+	// via the ReconcilePausedAnnotation. The condition gets removed once the reconcile is resumed.
+	ReconcilePausedCondition condition.Type = "ReconcilePaused"
+
+	// ExporterReadyCondition Status=True condition which indicates if the stats exporter is deployed and running
+	ExporterReadyCondition condition.Type = "ExporterReady"
+
+	// DBBackupReadyCondition Status=True condition which indicates if the DB dump got stored
+	DBBackupReadyCondition condition.Type = "DBBackupReady"
+
+	// APIPausedCondition Status=True condition which indicates if the API pods got stopped for the DB restore
+	APIPausedCondition condition.Type = "APIPaused"
+
+	// DBRestoreReadyCondition Status=True condition which indicates if the DB got restored and synced
+	DBRestoreReadyCondition condition.Type = "DBRestoreReady"
+
+	// PreUpgradeBackupReadyCondition Status=True condition which indicates if the DB got dumped before the last upgrade
+	PreUpgradeBackupReadyCondition condition.Type = "PreUpgradeBackupReady"
+)
+
+// Octavia Reasons used by API objects.
+const (
+	// InUseReason (Severity=Warning) documents a condition not in Status=True because the
+	// underlying Octavia resource is still in use and can not be deleted.
+	InUseReason = "InUse"
+
+	// MaintenanceReason (Severity=Info) documents a condition not in Status=True because the
+	// service is in maintenance mode.
+	MaintenanceReason = "Maintenance"
+)
+
+// Octavia Messages used by API objects.
+const (
+	//
+	// OctaviaAPIReady condition messages
+	//
+	// OctaviaAPIReadyInitMessage
+	OctaviaAPIReadyInitMessage = "OctaviaAPI not started"
+
+	// OctaviaAPIReadyMessage
+	OctaviaAPIReadyMessage = "OctaviaAPI ready"
+
+	// OctaviaAPIReadyNotFoundMessage
+	OctaviaAPIReadyNotFoundMessage = "OctaviaAPI not found"
+
+	// OctaviaAPIReadyWaitingMessage
+	OctaviaAPIReadyWaitingMessage = "OctaviaAPI not yet ready"
+
+	// OctaviaAPIReadyErrorMessage
+	OctaviaAPIReadyErrorMessage = "OctaviaAPI error occured %s"
+
+	//
+	// FlavorProfileReady condition messages
+	//
+	// FlavorProfileReadyInitMessage
+	FlavorProfileReadyInitMessage = "Flavor profile not started"
+
+	// FlavorProfileReadyMessage
+	FlavorProfileReadyMessage = "Flavor profile %s ready"
+
+	// FlavorProfileReadyWaitingMessage
+	FlavorProfileReadyWaitingMessage = "Flavor profile %s not yet ready"
+
+	// FlavorProfileReadyErrorMessage
+	FlavorProfileReadyErrorMessage = "Flavor profile error occured %s"
+
+	// FlavorProfileInUseMessage
+	FlavorProfileInUseMessage = "Flavor profile is still used by %d flavor(s)"
+
+	//
+	// FlavorReady condition messages
+	//
+	// FlavorReadyInitMessage
+	FlavorReadyInitMessage = "Flavor not started"
+
+	// FlavorReadyMessage
+	FlavorReadyMessage = "Flavor %s ready"
+
+	// FlavorReadyErrorMessage
+	FlavorReadyErrorMessage = "Flavor error occured %s"
+
+	// FlavorInUseMessage
+	FlavorInUseMessage = "Flavor is still used by %d load balancer(s)"
+
+	//
+	// OVNDBReady condition messages
+	//
+	// OVNDBReadyMessage
+	OVNDBReadyMessage = "OVN NB and SB DB endpoints discovered"
+
+	// OVNDBReadyWaitingMessage
+	OVNDBReadyWaitingMessage = "OVN %s DB endpoint not yet available"
+
+	// OVNDBReadyErrorMessage
+	OVNDBReadyErrorMessage = "OVN DB discovery error occured %s"
+
+	//
+	// MemcachedReady condition messages
+	//
+	// MemcachedReadyMessage
+	MemcachedReadyMessage = "Memcached servers discovered"
+
+	// MemcachedReadyWaitingMessage
+	MemcachedReadyWaitingMessage = "Memcached %s not yet ready"
+
+	// MemcachedReadyErrorMessage
+	MemcachedReadyErrorMessage = "Memcached discovery error occured %s"
+
+	//
+	// ApplicationCredentialReady condition messages
+	//
+	// ApplicationCredentialReadyMessage
+	ApplicationCredentialReadyMessage = "Application credential ready"
+
+	// ApplicationCredentialReadyErrorMessage
+	ApplicationCredentialReadyErrorMessage = "Application credential error occured %s"
+
+	//
+	// ReconcilePaused condition messages
+	//
+	// ReconcilePausedMessage
+	ReconcilePausedMessage = "Reconcile paused by the %s annotation"
+
+	//
+	// ExporterReady condition messages
+	//
+	// ExporterReadyInitMessage
+	ExporterReadyInitMessage = "Exporter not started"
+
+	// ExporterReadyMessage
+	ExporterReadyMessage = "Exporter ready"
+
+	// ExporterReadyRunningMessage
+	ExporterReadyRunningMessage = "Exporter deployment in progress"
+
+	// ExporterReadyErrorMessage
+	ExporterReadyErrorMessage = "Exporter error occured %s"
+
+	//
+	// DBBackupReady condition messages
+	//
+	// DBBackupReadyInitMessage
+	DBBackupReadyInitMessage = "DB backup not started"
+
+	// DBBackupReadyMessage
+	DBBackupReadyMessage = "DB backup %s completed"
+
+	// DBBackupReadyRunningMessage
+	DBBackupReadyRunningMessage = "DB backup in progress"
+
+	// DBBackupReadyWaitingMessage
+	DBBackupReadyWaitingMessage = "DB backup %s not yet completed"
+
+	// DBBackupReadyErrorMessage
+	DBBackupReadyErrorMessage = "DB backup error occured %s"
+
+	//
+	// APIPaused condition messages
+	//
+	// APIPausedInitMessage
+	APIPausedInitMessage = "API not paused"
+
+	// APIPausedMessage
+	APIPausedMessage = "API pods stopped"
+
+	// APIPausedRunningMessage
+	APIPausedRunningMessage = "Waiting for %d API pod(s) to stop"
+
+	// APIPausedConflictMessage
+	APIPausedConflictMessage = "API paused by the DB restore %s"
+
+	//
+	// DBRestoreReady condition messages
+	//
+	// DBRestoreReadyInitMessage
+	DBRestoreReadyInitMessage = "DB restore not started"
+
+	// DBRestoreReadyMessage
+	DBRestoreReadyMessage = "DB restored from %s"
+
+	// DBRestoreReadyRunningMessage
+	DBRestoreReadyRunningMessage = "DB restore and db-sync in progress"
+
+	// DBRestoreReadyErrorMessage
+	DBRestoreReadyErrorMessage = "DB restore error occured %s"
+
+	//
+	// PreUpgradeBackupReady condition messages
+	//
+	// PreUpgradeBackupReadyMessage
+	PreUpgradeBackupReadyMessage = "Pre-upgrade DB backup %s completed"
+
+	// PreUpgradeBackupReadyRunningMessage
+	PreUpgradeBackupReadyRunningMessage = "Pre-upgrade DB backup in progress"
+
+	// PreUpgradeBackupReadyErrorMessage
+	PreUpgradeBackupReadyErrorMessage = "Pre-upgrade DB backup error occured %s"
+
+	// PreUpgradeBackupReadySkippedMessage
+	PreUpgradeBackupReadySkippedMessage = "Pre-upgrade DB backup failed, skipped by the %s annotation"
+
+	//
+	// DeploymentReady condition messages
+	//
+	// DeploymentReadyRolloutMessage
+	DeploymentReadyRolloutMessage = "Deployment rollout in progress, %d of %d replicas updated and available"
+
+	// DeploymentReadyMaintenanceMessage
+	DeploymentReadyMaintenanceMessage = "Deployment scaled to zero for maintenance"
+)
@@ -22,7 +22,9 @@ import (
 	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
@@ -31,6 +33,19 @@ const (
 
 	// DeploymentHash hash used to detect changes
 	DeploymentHash = "deployment"
+
+	// ReconcilePausedAnnotation - if set to "true" on an OctaviaAPI, the operator stops to
+	// create, update or delete any resources of it and only reports its status. This also
+	// blocks the deletion of the OctaviaAPI until the annotation gets removed.
+	ReconcilePausedAnnotation = "octavia.openstack.org/reconcile-paused"
+
+	// DBRestoreAnnotation - set on the OctaviaAPI by an OctaviaDBRestore to the name of the restore
+	// while it restores the DB. The API gets scaled to zero like in maintenance mode.
+	DBRestoreAnnotation = "octavia.openstack.org/db-restore"
+
+	// SkipPreUpgradeBackupAnnotation - if set to "true" on an OctaviaAPI, the DB upgrade proceeds
+	// although the pre-upgrade backup failed
+	SkipPreUpgradeBackupAnnotation = "octavia.openstack.org/skip-pre-upgrade-backup"
 )
 
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
@@ -65,7 +80,7 @@ type OctaviaAPISpec struct {
 	// +kubebuilder:default=1
 	// +kubebuilder:validation:Maximum=32
 	// +kubebuilder:validation:Minimum=0
-	// Replicas of octavia API to run
+	// Replicas of octavia API to run, ignored while autoscaling is enabled
 	Replicas int32 `json:"replicas"`
 
 	// +kubebuilder:validation:Required
@@ -80,6 +95,29 @@ type OctaviaAPISpec struct {
 	// NodeSelector to target subset of worker nodes running this service
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 
+	// +kubebuilder:validation:Optional
+	// MemcachedInstance - name of the Memcached instance in the namespace used by keystonemiddleware
+	// to cache the validated tokens. If not set, every request gets validated against keystone.
+	MemcachedInstance string `json:"memcachedInstance,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Tolerations - tolerations of the API and db-sync pods
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// TopologySpreadConstraints - spread the pods e.g. across zones using the topology.kubernetes.io/zone
+	// topology key. If no labelSelector is set, the constraint selects the pods of the same deployment.
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// PriorityClassName - priority class of the API and db-sync pods
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Affinity - overrides the default affinity of the pods, which prefers to run the API pods on
+	// different nodes
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// Debug - enable debug for different deploy stages. If an init container is used, it runs and the
 	// actual action pod gets started with sleep infinity
@@ -90,6 +128,21 @@ type OctaviaAPISpec struct {
 	// PreserveJobs - do not delete jobs after they finished e.g. to check logs
 	PreserveJobs bool `json:"preserveJobs,omitempty"`
 
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={backoffLimit: 6}
+	// DBSyncJob - retries, timeout and cleanup of the db-sync job
+	DBSyncJob OctaviaDBSyncJob `json:"dbSyncJob,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={schedule: "1 0 * * *"}
+	// DBPurge - settings of the cronjob purging the deleted load balancers and amphorae from the DB
+	DBPurge OctaviaDBPurge `json:"dbPurge,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={retention: 3}
+	// PreUpgradeBackup - dump the DB before it gets upgraded by the db-sync of a new container image
+	PreUpgradeBackup OctaviaPreUpgradeBackup `json:"preUpgradeBackup,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default="# add your customization here"
 	// CustomServiceConfig - customize the service config using this parameter to change service defaults,
@@ -107,8 +160,114 @@ type OctaviaAPISpec struct {
 	// Resources - Compute Resources required by this service (Limits/Requests).
 	// https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={amphora}
+	// EnabledProviders - list of Octavia provider drivers to enable, the first one is the default provider.
+	// If ovn is enabled, the OVN NB/SB DB endpoints get discovered from the OVNDBCluster CRs in the
+	// namespace and the octavia-driver-agent gets deployed as sidecar of the API.
+	EnabledProviders []ProviderDriver `json:"enabledProviders,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=9876
+	// +kubebuilder:validation:Minimum=1024
+	// +kubebuilder:validation:Maximum=65535
+	// APIPort - port the API listens on, used for the container, the probes and the services.
+	// httpd runs as non-root without NET_BIND_SERVICE, privileged ports are not allowed.
+	APIPort int32 `json:"apiPort,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Endpoints - per endpoint port and TLS settings of the httpd virtual hosts
+	Endpoints OctaviaAPIEndpoints `json:"endpoints,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=5
+	// +kubebuilder:validation:Minimum=1
+	// APIWorkers - number of WSGI daemon processes serving the API in each pod
+	APIWorkers int32 `json:"apiWorkers,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// APIThreads - number of threads of each WSGI daemon process
+	APIThreads int32 `json:"apiThreads,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=1
+	// APITimeout - timeout in seconds for API requests handled by httpd
+	APITimeout int32 `json:"apiTimeout,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={paginationMaxLimit: 1000}
+	// APISettings - settings rendered into the [api_settings] section of octavia.conf
+	APISettings OctaviaAPISettings `json:"apiSettings,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={workers: 4}
+	// ControllerWorker - settings rendered into the [controller_worker] section of octavia.conf
+	ControllerWorker OctaviaControllerWorker `json:"controllerWorker,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={timeoutClientData: 50000}
+	// HAProxyAmphora - settings rendered into the [haproxy_amphora] section of octavia.conf
+	HAProxyAmphora OctaviaHAProxyAmphora `json:"haproxyAmphora,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={enableAntiAffinity: true}
+	// Nova - settings rendered into the [nova] section of octavia.conf
+	Nova OctaviaNova `json:"nova,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// Maintenance - scale the API deployment to zero, e.g. during DB maintenance. The database
+	// and the keystone service and endpoints are kept.
+	Maintenance bool `json:"maintenance,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=0
+	// MaxUnavailable - maximum number of API pods, or percentage of the replicas, which can be
+	// unavailable during a rolling update
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// MaxSurge - maximum number of API pods, or percentage of the replicas, which can be created
+	// above the desired replicas during a rolling update
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={minReplicas: 1}
+	// Autoscaling - scale the API deployment using a HorizontalPodAutoscaler instead of Replicas
+	Autoscaling OctaviaAutoscaling `json:"autoscaling,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={port: 9120}
+	// Exporter - optional Prometheus exporter for the load balancer, listener and amphora statistics
+	Exporter OctaviaExporter `json:"exporter,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={rotationPeriod: "720h"}
+	// ApplicationCredential - authenticate the service user using a keystone application credential
+	// managed by the operator instead of the password
+	ApplicationCredential OctaviaApplicationCredential `json:"applicationCredential,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Override - overrides of the services and the route exposing the API endpoints
+	Override OctaviaOverride `json:"override,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=amphora;ovn
+// ProviderDriver - name of an Octavia provider driver
+type ProviderDriver string
+
+const (
+	// ProviderDriverAmphora - the Octavia amphora provider driver
+	ProviderDriverAmphora ProviderDriver = "amphora"
+	// ProviderDriverOVN - the OVN provider driver
+	ProviderDriverOVN ProviderDriver = "ovn"
+)
+
 // PasswordSelector to identify the DB and AdminUser password from the Secret
 type PasswordSelector struct {
 	// +kubebuilder:validation:Optional
@@ -134,6 +293,351 @@ type OctaviaAPIDebug struct {
 	Service bool `json:"service,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=TLSv1;TLSv1.1;TLSv1.2;TLSv1.3
+// TLSVersion - a TLS protocol version as used by Octavia
+type TLSVersion string
+
+// OctaviaAPISettings - typed [api_settings] options. Options set via CustomServiceConfig
+// take precedence over the ones rendered from these fields.
+type OctaviaAPISettings struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={"TLSv1.2","TLSv1.3"}
+	// DefaultListenerTLSVersions - TLS versions used for TERMINATED_HTTPS listeners if none are specified
+	DefaultListenerTLSVersions []TLSVersion `json:"defaultListenerTLSVersions,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={"TLSv1.2","TLSv1.3"}
+	// DefaultPoolTLSVersions - TLS versions used for TLS enabled pools if none are specified
+	DefaultPoolTLSVersions []TLSVersion `json:"defaultPoolTLSVersions,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	// AllowTLSTerminatedListeners - allow users to create TERMINATED_HTTPS listeners
+	AllowTLSTerminatedListeners *bool `json:"allowTLSTerminatedListeners,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	// AllowPingHealthMonitors - allow users to create PING health monitors
+	AllowPingHealthMonitors *bool `json:"allowPingHealthMonitors,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	// AllowPrometheusListeners - allow users to create PROMETHEUS listeners
+	AllowPrometheusListeners *bool `json:"allowPrometheusListeners,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1000
+	// +kubebuilder:validation:Minimum=1
+	// PaginationMaxLimit - maximum number of items returned in a single API response
+	PaginationMaxLimit int32 `json:"paginationMaxLimit,omitempty"`
+}
+
+// OctaviaControllerWorker - typed [controller_worker] options
+type OctaviaControllerWorker struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=4
+	// +kubebuilder:validation:Minimum=1
+	// Workers - number of workers for the controller-worker service
+	Workers int32 `json:"workers,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=SINGLE
+	// +kubebuilder:validation:Enum=SINGLE;ACTIVE_STANDBY
+	// LoadBalancerTopology - load balancer topology used for amphora load balancers
+	LoadBalancerTopology string `json:"loadBalancerTopology,omitempty"`
+}
+
+// OctaviaHAProxyAmphora - typed [haproxy_amphora] options, all timeouts are in milliseconds
+type OctaviaHAProxyAmphora struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=50000
+	// +kubebuilder:validation:Minimum=0
+	// TimeoutClientData - frontend client inactivity timeout
+	TimeoutClientData *int32 `json:"timeoutClientData,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=5000
+	// +kubebuilder:validation:Minimum=0
+	// TimeoutMemberConnect - backend member connection timeout
+	TimeoutMemberConnect *int32 `json:"timeoutMemberConnect,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=50000
+	// +kubebuilder:validation:Minimum=0
+	// TimeoutMemberData - backend member inactivity timeout
+	TimeoutMemberData *int32 `json:"timeoutMemberData,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	// TimeoutTCPInspect - time to wait for TCP packets for content inspection
+	TimeoutTCPInspect *int32 `json:"timeoutTCPInspect,omitempty"`
+}
+
+// OctaviaNova - typed [nova] options
+type OctaviaNova struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	// EnableAntiAffinity - use a nova server group to spread the amphorae of ACTIVE_STANDBY load balancers
+	EnableAntiAffinity *bool `json:"enableAntiAffinity,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=anti-affinity
+	// +kubebuilder:validation:Enum=anti-affinity;soft-anti-affinity
+	// AntiAffinityPolicy - nova server group policy used if anti affinity is enabled
+	AntiAffinityPolicy string `json:"antiAffinityPolicy,omitempty"`
+}
+
+// OctaviaAutoscaling - settings of the HorizontalPodAutoscaler of the API deployment
+type OctaviaAutoscaling struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// Enabled - manage a HorizontalPodAutoscaler for the API deployment
+	Enabled bool `json:"enabled,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=32
+	// MinReplicas - lower limit of API replicas
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=32
+	// MaxReplicas - upper limit of API replicas, raised to MinReplicas if lower
+	MaxReplicas int32 `json:"maxReplicas,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// TargetCPUUtilization - target average CPU utilization of the API pods in percent of the
+	// requested CPU, requires CPU requests in Resources. Defaults to 80 unless
+	// TargetRequestsPerSecond is set, leave it unset to only scale on the request rate.
+	TargetCPUUtilization *int32 `json:"targetCPUUtilization,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// TargetRequestsPerSecond - target average request rate per API pod. Requires a custom metrics
+	// adapter which provides the RequestRateMetric for the pods.
+	TargetRequestsPerSecond *resource.Quantity `json:"targetRequestsPerSecond,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=http_requests_per_second
+	// RequestRateMetric - name of the pods metric used for TargetRequestsPerSecond
+	RequestRateMetric string `json:"requestRateMetric,omitempty"`
+}
+
+// OctaviaExporter - settings of the Prometheus exporter which polls the load balancer statistics from
+// the Octavia API using the service user
+type OctaviaExporter struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// Enabled - deploy the exporter and a ServiceMonitor for it
+	Enabled bool `json:"enabled,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="quay.io/openstack-k8s-operators/octavia-operator:latest"
+	// ContainerImage - image holding the /octavia-exporter binary, by default the operator image
+	ContainerImage string `json:"containerImage,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=9120
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// Port - port the exporter serves the metrics on
+	Port int32 `json:"port,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="60s"
+	// PollInterval - interval to poll the statistics from the Octavia API, also used as scrape interval
+	PollInterval metav1.Duration `json:"pollInterval,omitempty"`
+}
+
+// OctaviaDBPurge - settings of the DB purge cronjob
+type OctaviaDBPurge struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="1 0 * * *"
+	// Schedule - cron schedule of the DB purge job
+	Schedule string `json:"schedule,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=30
+	// +kubebuilder:validation:Minimum=1
+	// Age - number of days after which deleted load balancers and amphorae get purged
+	Age int `json:"age,omitempty"`
+}
+
+// OctaviaDBSyncJob - settings of the db-sync job
+type OctaviaDBSyncJob struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=6
+	// +kubebuilder:validation:Minimum=0
+	// BackoffLimit - number of retries of failed db-sync pods before the job is failed
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// ActiveDeadlineSeconds - time in seconds the job may run, including its retries, before it
+	// is failed. Not limited by default.
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// TTLSecondsAfterFinished - time in seconds after which a finished job gets removed. A failed
+	// job which gets removed runs again. By default a failed job is kept.
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
+// OctaviaPreUpgradeBackup - settings of the DB backup before an upgrade
+type OctaviaPreUpgradeBackup struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// Enabled - dump the DB before the db-sync of a new container image upgrades it. The upgrade
+	// does not proceed if the backup fails, unless the skip-pre-upgrade-backup annotation is set.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// PersistentVolumeClaim - name of an existing PVC in the namespace the dump gets written to,
+	// required if enabled
+	PersistentVolumeClaim string `json:"persistentVolumeClaim,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=1
+	// Retention - number of dumps of the Octavia DB kept in the PVC
+	Retention int `json:"retention,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="quay.io/tripleozedcentos9/openstack-mariadb:current-tripleo"
+	// ContainerImage - image providing the mysql client, used to dump the DB
+	ContainerImage string `json:"containerImage,omitempty"`
+}
+
+// OctaviaPreUpgradeBackupStatus - the DB backup before the last upgrade
+type OctaviaPreUpgradeBackupStatus struct {
+	// ContainerImage - the image the DB got upgraded to after the backup
+	ContainerImage string `json:"containerImage"`
+
+	// StartTime - when the backup got started
+	StartTime metav1.Time `json:"startTime"`
+
+	// Location - the PVC and file name of the dump
+	Location string `json:"location,omitempty"`
+
+	// CompletionTime - when the dump got stored
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// OctaviaApplicationCredential - settings of the application credential of the service user
+type OctaviaApplicationCredential struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// Enabled - create a restricted application credential for the service user and use it in the
+	// [service_auth] section. The credential gets stored in the <name>-application-credential secret.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Roles - roles of the service user in the service project the credential is limited to, by
+	// default all roles of the user
+	Roles []string `json:"roles,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="720h"
+	// RotationPeriod - interval a new credential gets created in
+	RotationPeriod metav1.Duration `json:"rotationPeriod,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="24h"
+	// GracePeriod - time the previous credential stays valid after the rotation, has to cover the
+	// rollout of the API pods using the new credential
+	GracePeriod metav1.Duration `json:"gracePeriod,omitempty"`
+}
+
+// OctaviaAPIEndpoints - settings of the admin, internal and public endpoints
+type OctaviaAPIEndpoints struct {
+	// +kubebuilder:validation:Optional
+	// Admin - settings of the admin endpoint
+	Admin OctaviaAPIEndpoint `json:"admin,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Internal - settings of the internal endpoint
+	Internal OctaviaAPIEndpoint `json:"internal,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Public - settings of the public endpoint
+	Public OctaviaAPIEndpoint `json:"public,omitempty"`
+}
+
+// OctaviaAPIEndpoint - settings of the httpd virtual host and the service of an endpoint.
+// Endpoints with the same port share the virtual host and need the same TLS settings.
+type OctaviaAPIEndpoint struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1024
+	// +kubebuilder:validation:Maximum=65535
+	// Port - port of the virtual host and the service of the endpoint, by default APIPort.
+	// httpd runs as non-root without NET_BIND_SERVICE, privileged ports are not allowed.
+	Port int32 `json:"port,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// TLSSecret - name of a kubernetes.io/tls secret. If set the virtual host terminates TLS
+	// using its tls.crt and tls.key and the endpoint gets registered as https.
+	TLSSecret string `json:"tlsSecret,omitempty"`
+}
+
+// OctaviaOverride - overrides of the services and the route exposing the API endpoints
+type OctaviaOverride struct {
+	// +kubebuilder:validation:Optional
+	// Service - overrides of the service per endpoint, the keys are admin, internal and public
+	Service map[endpoint.Endpoint]OctaviaServiceOverride `json:"service,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// PublicRoute - overrides of the route of the public endpoint
+	PublicRoute OctaviaRouteOverride `json:"publicRoute,omitempty"`
+}
+
+// OctaviaServiceOverride - overrides of the service of an endpoint. Endpoints with a service of
+// type LoadBalancer or NodePort get no route, the endpoint URL points to the service.
+type OctaviaServiceOverride struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=ClusterIP;LoadBalancer;NodePort
+	// Type - type of the service, by default ClusterIP
+	Type corev1.ServiceType `json:"type,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// LoadBalancerIP - requested IP of a service of type LoadBalancer, e.g. from a MetalLB pool
+	LoadBalancerIP string `json:"loadBalancerIP,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=SingleStack;PreferDualStack;RequireDualStack
+	// IPFamilyPolicy - set to PreferDualStack or RequireDualStack for a dual-stack service
+	IPFamilyPolicy *corev1.IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// IPFamilies - IP families of the service, the first is the primary family
+	IPFamilies []corev1.IPFamily `json:"ipFamilies,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Annotations - additional annotations of the service, e.g. metallb.universe.tf/address-pool
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Labels - additional labels of the service
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// OctaviaRouteOverride - overrides of the route of the public endpoint
+type OctaviaRouteOverride struct {
+	// +kubebuilder:validation:Optional
+	// Enabled - create a route for the public endpoint, by default true. Without route the public
+	// endpoint URL points to the public service.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Host - custom hostname of the route, by default generated by the router
+	Host string `json:"host,omitempty"`
+}
+
 // OctaviaAPIStatus defines the observed state of OctaviaAPI
 type OctaviaAPIStatus struct {
 	// ReadyCount of octavia API instances
@@ -153,10 +657,30 @@ type OctaviaAPIStatus struct {
 
 	// ServiceID - the ID of the registered service in keystone
 	ServiceID string `json:"serviceID,omitempty"`
+
+	// Replicas - current number of API pods
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// DesiredReplicas - desired number of API pods, set by the autoscaler if enabled
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+
+	// ObservedGeneration - the most recent generation of the spec the status got reconciled for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ApplicationCredentialID - the ID of the application credential in use, if enabled
+	ApplicationCredentialID string `json:"applicationCredentialID,omitempty"`
+
+	// DBSyncImage - the container image the DB got synced with last
+	DBSyncImage string `json:"dbSyncImage,omitempty"`
+
+	// PreUpgradeBackup - the DB backup before the last upgrade, if enabled
+	PreUpgradeBackup *OctaviaPreUpgradeBackupStatus `json:"preUpgradeBackup,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyCount",description="Ready replicas"
+//+kubebuilder:printcolumn:name="Desired",type="integer",JSONPath=".status.desiredReplicas",description="Desired replicas"
 //+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[0].status",description="Status"
 //+kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[0].message",description="Message"
 
@@ -190,8 +714,76 @@ func (instance OctaviaAPI) GetEndpoint(endpointType endpoint.Endpoint) (string,
 	return "", fmt.Errorf("%s endpoint not found", string(endpointType))
 }
 
+// IsProviderEnabled - returns true if the provider driver is in the list of enabled providers
+func (instance OctaviaAPI) IsProviderEnabled(provider ProviderDriver) bool {
+	for _, p := range instance.Spec.EnabledProviders {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
+
+// IsReconcilePaused - returns true if the reconcile of the instance is paused via annotation
+func (instance OctaviaAPI) IsReconcilePaused() bool {
+	return instance.GetAnnotations()[ReconcilePausedAnnotation] == "true"
+}
+
+// IsMaintenance - returns true if the API is scaled to zero, in maintenance mode or while the DB
+// gets restored
+func (instance OctaviaAPI) IsMaintenance() bool {
+	return instance.Spec.Maintenance || instance.GetAnnotations()[DBRestoreAnnotation] != ""
+}
+
+// GetReplicas - returns the number of API replicas to run, zero in maintenance mode and the
+// minimum replicas if autoscaling is enabled
+func (instance OctaviaAPI) GetReplicas() int32 {
+	if instance.IsMaintenance() {
+		return 0
+	}
+	if instance.IsAutoscalingEnabled() {
+		return instance.Spec.Autoscaling.MinReplicas
+	}
+	return instance.Spec.Replicas
+}
+
+// IsAutoscalingEnabled - returns true if the replicas are managed by the autoscaler, which is
+// not the case in maintenance mode
+func (instance OctaviaAPI) IsAutoscalingEnabled() bool {
+	return instance.Spec.Autoscaling.Enabled && !instance.IsMaintenance()
+}
+
 // IsReady - returns true if service is ready to server requests
 func (instance OctaviaAPI) IsReady() bool {
 	return instance.Status.Conditions.IsTrue(condition.ExposeServiceReadyCondition) &&
 		instance.Status.Conditions.IsTrue(condition.DeploymentReadyCondition)
 }
+
+// GetEndpointSettings - returns the port and TLS settings of the endpoint
+func (instance OctaviaAPI) GetEndpointSettings(endpointType endpoint.Endpoint) OctaviaAPIEndpoint {
+	switch endpointType {
+	case endpoint.EndpointAdmin:
+		return instance.Spec.Endpoints.Admin
+	case endpoint.EndpointInternal:
+		return instance.Spec.Endpoints.Internal
+	case endpoint.EndpointPublic:
+		return instance.Spec.Endpoints.Public
+	}
+	return OctaviaAPIEndpoint{}
+}
+
+// GetServiceOverride - returns the service override of the endpoint
+func (instance OctaviaAPI) GetServiceOverride(endpointType endpoint.Endpoint) OctaviaServiceOverride {
+	return instance.Spec.Override.Service[endpointType]
+}
+
+// HasRoute - returns true if the endpoint gets exposed using a route. The public endpoint has one
+// unless disabled, the admin and internal endpoints unless their service type is overridden.
+func (instance OctaviaAPI) HasRoute(endpointType endpoint.Endpoint) bool {
+	if endpointType == endpoint.EndpointPublic {
+		enabled := instance.Spec.Override.PublicRoute.Enabled
+		return enabled == nil || *enabled
+	}
+	svcType := instance.GetServiceOverride(endpointType).Type
+	return svcType == "" || svcType == corev1.ServiceTypeClusterIP
+}
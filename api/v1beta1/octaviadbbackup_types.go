@@ -0,0 +1,117 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OctaviaDBBackupSpec defines the desired state of OctaviaDBBackup
+type OctaviaDBBackupSpec struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="quay.io/tripleozedcentos9/openstack-mariadb:current-tripleo"
+	// ContainerImage - image providing the mysql client, used to dump the DB
+	ContainerImage string `json:"containerImage,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// Target - where the dump gets stored
+	Target OctaviaDBBackupTarget `json:"target"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=7
+	// +kubebuilder:validation:Minimum=1
+	// Retention - number of dumps of the Octavia DB kept in the target, older ones get removed
+	// after the dump succeeded
+	Retention int `json:"retention,omitempty"`
+}
+
+// OctaviaDBBackupTarget - the PVC or object store the dump gets stored in, exactly one has to be set
+type OctaviaDBBackupTarget struct {
+	// +kubebuilder:validation:Optional
+	// PersistentVolumeClaim - name of an existing PVC in the namespace the dump gets written to
+	PersistentVolumeClaim string `json:"persistentVolumeClaim,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ObjectStore - S3 compatible bucket the dump gets uploaded to
+	ObjectStore *OctaviaDBBackupObjectStore `json:"objectStore,omitempty"`
+}
+
+// OctaviaDBBackupObjectStore - S3 compatible bucket
+type OctaviaDBBackupObjectStore struct {
+	// +kubebuilder:validation:Required
+	// URL - URL of the bucket using path style, e.g. https://s3.example.com/octavia-backups
+	URL string `json:"url"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="us-east-1"
+	// Region - region used to sign the requests
+	Region string `json:"region,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// Secret - name of the secret holding the AccessKeyID and SecretAccessKey of the bucket
+	Secret string `json:"secret"`
+}
+
+// OctaviaDBBackupStatus defines the observed state of OctaviaDBBackup
+type OctaviaDBBackupStatus struct {
+	// FileName - name of the dump in the target
+	FileName string `json:"fileName,omitempty"`
+
+	// Location - the PVC or URL of the dump
+	Location string `json:"location,omitempty"`
+
+	// CompletionTime - when the dump got stored
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Conditions
+	Conditions condition.Conditions `json:"conditions,omitempty" optional:"true"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Location",type="string",JSONPath=".status.location",description="Location"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[0].status",description="Status"
+//+kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[0].message",description="Message"
+
+// OctaviaDBBackup is the Schema for the octaviadbbackups API
+type OctaviaDBBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OctaviaDBBackupSpec   `json:"spec,omitempty"`
+	Status OctaviaDBBackupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OctaviaDBBackupList contains a list of OctaviaDBBackup
+type OctaviaDBBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OctaviaDBBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OctaviaDBBackup{}, &OctaviaDBBackupList{})
+}
+
+// IsReady - returns true if the dump got stored
+func (instance OctaviaDBBackup) IsReady() bool {
+	return instance.Status.CompletionTime != nil &&
+		instance.Status.Conditions.IsTrue(DBBackupReadyCondition)
+}
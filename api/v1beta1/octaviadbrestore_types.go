@@ -0,0 +1,80 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OctaviaDBRestoreSpec defines the desired state of OctaviaDBRestore
+type OctaviaDBRestoreSpec struct {
+	// +kubebuilder:validation:Required
+	// Backup - name of the OctaviaDBBackup in the same namespace to restore
+	Backup string `json:"backup"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="quay.io/tripleozedcentos9/openstack-mariadb:current-tripleo"
+	// ContainerImage - image providing the mysql client, used to restore the DB
+	ContainerImage string `json:"containerImage,omitempty"`
+}
+
+// OctaviaDBRestoreStatus defines the observed state of OctaviaDBRestore
+type OctaviaDBRestoreStatus struct {
+	// Location - the PVC or URL of the restored dump
+	Location string `json:"location,omitempty"`
+
+	// CompletionTime - when the DB got restored and synced
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Conditions
+	Conditions condition.Conditions `json:"conditions,omitempty" optional:"true"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Backup",type="string",JSONPath=".spec.backup",description="Backup"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[0].status",description="Status"
+//+kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[0].message",description="Message"
+
+// OctaviaDBRestore is the Schema for the octaviadbrestores API
+type OctaviaDBRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OctaviaDBRestoreSpec   `json:"spec,omitempty"`
+	Status OctaviaDBRestoreStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OctaviaDBRestoreList contains a list of OctaviaDBRestore
+type OctaviaDBRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OctaviaDBRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OctaviaDBRestore{}, &OctaviaDBRestoreList{})
+}
+
+// IsReady - returns true if the DB got restored
+func (instance OctaviaDBRestore) IsReady() bool {
+	return instance.Status.CompletionTime != nil &&
+		instance.Status.Conditions.IsTrue(DBRestoreReadyCondition)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OctaviaFlavorSpec defines the desired state of OctaviaFlavor
+type OctaviaFlavorSpec struct {
+	// +kubebuilder:validation:Optional
+	// Name - name of the flavor in Octavia, defaults to the name of the OctaviaFlavor
+	Name string `json:"name,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Description - description of the flavor
+	Description string `json:"description,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// FlavorProfile - name of the OctaviaFlavorProfile in the same namespace the flavor uses
+	FlavorProfile string `json:"flavorProfile"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	// Enabled - if the flavor is available to users
+	Enabled bool `json:"enabled"`
+}
+
+// OctaviaFlavorStatus defines the observed state of OctaviaFlavor
+type OctaviaFlavorStatus struct {
+	// FlavorID - the ID of the flavor in Octavia
+	FlavorID string `json:"flavorID,omitempty"`
+
+	// FlavorProfileID - the ID of the flavor profile in Octavia the flavor got created with
+	FlavorProfileID string `json:"flavorProfileID,omitempty"`
+
+	// Conditions
+	Conditions condition.Conditions `json:"conditions,omitempty" optional:"true"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.flavorID",description="Flavor ID"
+//+kubebuilder:printcolumn:name="Profile",type="string",JSONPath=".spec.flavorProfile",description="Flavor profile"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[0].status",description="Status"
+//+kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[0].message",description="Message"
+
+// OctaviaFlavor is the Schema for the octaviaflavors API
+type OctaviaFlavor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OctaviaFlavorSpec   `json:"spec,omitempty"`
+	Status OctaviaFlavorStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OctaviaFlavorList contains a list of OctaviaFlavor
+type OctaviaFlavorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OctaviaFlavor `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OctaviaFlavor{}, &OctaviaFlavorList{})
+}
+
+// GetFlavorName - returns the name of the flavor in Octavia
+func (instance OctaviaFlavor) GetFlavorName() string {
+	if instance.Spec.Name != "" {
+		return instance.Spec.Name
+	}
+	return instance.Name
+}
+
+// IsReady - returns true if the flavor got created in Octavia
+func (instance OctaviaFlavor) IsReady() bool {
+	return instance.Status.FlavorID != "" &&
+		instance.Status.Conditions.IsTrue(FlavorReadyCondition)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OctaviaFlavorProfileSpec defines the desired state of OctaviaFlavorProfile
+type OctaviaFlavorProfileSpec struct {
+	// +kubebuilder:validation:Optional
+	// Name - name of the flavor profile in Octavia, defaults to the name of the OctaviaFlavorProfile
+	Name string `json:"name,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=amphora
+	// ProviderName - name of the Octavia provider driver the flavor profile is used with
+	ProviderName string `json:"providerName"`
+
+	// +kubebuilder:validation:Required
+	// FlavorData - JSON formatted provider specific flavor metadata,
+	// e.g. {"loadbalancer_topology": "ACTIVE_STANDBY", "compute_flavor": "<nova flavor id>"}
+	FlavorData string `json:"flavorData"`
+}
+
+// OctaviaFlavorProfileStatus defines the observed state of OctaviaFlavorProfile
+type OctaviaFlavorProfileStatus struct {
+	// FlavorProfileID - the ID of the flavor profile in Octavia
+	FlavorProfileID string `json:"flavorProfileID,omitempty"`
+
+	// Conditions
+	Conditions condition.Conditions `json:"conditions,omitempty" optional:"true"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.flavorProfileID",description="Flavor profile ID"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[0].status",description="Status"
+//+kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[0].message",description="Message"
+
+// OctaviaFlavorProfile is the Schema for the octaviaflavorprofiles API
+type OctaviaFlavorProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OctaviaFlavorProfileSpec   `json:"spec,omitempty"`
+	Status OctaviaFlavorProfileStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OctaviaFlavorProfileList contains a list of OctaviaFlavorProfile
+type OctaviaFlavorProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OctaviaFlavorProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OctaviaFlavorProfile{}, &OctaviaFlavorProfileList{})
+}
+
+// GetFlavorProfileName - returns the name of the flavor profile in Octavia
+func (instance OctaviaFlavorProfile) GetFlavorProfileName() string {
+	if instance.Spec.Name != "" {
+		return instance.Spec.Name
+	}
+	return instance.Name
+}
+
+// IsReady - returns true if the flavor profile got created in Octavia
+func (instance OctaviaFlavorProfile) IsReady() bool {
+	return instance.Status.FlavorProfileID != "" &&
+		instance.Status.Conditions.IsTrue(FlavorProfileReadyCondition)
+}
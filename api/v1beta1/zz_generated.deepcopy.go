@@ -23,7 +23,10 @@ package v1beta1
 
 import (
 	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
+	"k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -68,6 +71,39 @@ func (in *OctaviaAPIDebug) DeepCopy() *OctaviaAPIDebug {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaAPIEndpoint) DeepCopyInto(out *OctaviaAPIEndpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaAPIEndpoint.
+func (in *OctaviaAPIEndpoint) DeepCopy() *OctaviaAPIEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaAPIEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaAPIEndpoints) DeepCopyInto(out *OctaviaAPIEndpoints) {
+	*out = *in
+	out.Admin = in.Admin
+	out.Internal = in.Internal
+	out.Public = in.Public
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaAPIEndpoints.
+func (in *OctaviaAPIEndpoints) DeepCopy() *OctaviaAPIEndpoints {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaAPIEndpoints)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OctaviaAPIList) DeepCopyInto(out *OctaviaAPIList) {
 	*out = *in
@@ -100,6 +136,46 @@ func (in *OctaviaAPIList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaAPISettings) DeepCopyInto(out *OctaviaAPISettings) {
+	*out = *in
+	if in.DefaultListenerTLSVersions != nil {
+		in, out := &in.DefaultListenerTLSVersions, &out.DefaultListenerTLSVersions
+		*out = make([]TLSVersion, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultPoolTLSVersions != nil {
+		in, out := &in.DefaultPoolTLSVersions, &out.DefaultPoolTLSVersions
+		*out = make([]TLSVersion, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowTLSTerminatedListeners != nil {
+		in, out := &in.AllowTLSTerminatedListeners, &out.AllowTLSTerminatedListeners
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowPingHealthMonitors != nil {
+		in, out := &in.AllowPingHealthMonitors, &out.AllowPingHealthMonitors
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowPrometheusListeners != nil {
+		in, out := &in.AllowPrometheusListeners, &out.AllowPrometheusListeners
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaAPISettings.
+func (in *OctaviaAPISettings) DeepCopy() *OctaviaAPISettings {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaAPISettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OctaviaAPISpec) DeepCopyInto(out *OctaviaAPISpec) {
 	*out = *in
@@ -111,7 +187,29 @@ func (in *OctaviaAPISpec) DeepCopyInto(out *OctaviaAPISpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
 	out.Debug = in.Debug
+	in.DBSyncJob.DeepCopyInto(&out.DBSyncJob)
+	out.DBPurge = in.DBPurge
+	out.PreUpgradeBackup = in.PreUpgradeBackup
 	if in.DefaultConfigOverwrite != nil {
 		in, out := &in.DefaultConfigOverwrite, &out.DefaultConfigOverwrite
 		*out = make(map[string]string, len(*in))
@@ -120,6 +218,30 @@ func (in *OctaviaAPISpec) DeepCopyInto(out *OctaviaAPISpec) {
 		}
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.EnabledProviders != nil {
+		in, out := &in.EnabledProviders, &out.EnabledProviders
+		*out = make([]ProviderDriver, len(*in))
+		copy(*out, *in)
+	}
+	out.Endpoints = in.Endpoints
+	in.APISettings.DeepCopyInto(&out.APISettings)
+	out.ControllerWorker = in.ControllerWorker
+	in.HAProxyAmphora.DeepCopyInto(&out.HAProxyAmphora)
+	in.Nova.DeepCopyInto(&out.Nova)
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	in.Autoscaling.DeepCopyInto(&out.Autoscaling)
+	out.Exporter = in.Exporter
+	in.ApplicationCredential.DeepCopyInto(&out.ApplicationCredential)
+	in.Override.DeepCopyInto(&out.Override)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaAPISpec.
@@ -156,6 +278,11 @@ func (in *OctaviaAPIStatus) DeepCopyInto(out *OctaviaAPIStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PreUpgradeBackup != nil {
+		in, out := &in.PreUpgradeBackup, &out.PreUpgradeBackup
+		*out = new(OctaviaPreUpgradeBackupStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaAPIStatus.
@@ -168,6 +295,729 @@ func (in *OctaviaAPIStatus) DeepCopy() *OctaviaAPIStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaApplicationCredential) DeepCopyInto(out *OctaviaApplicationCredential) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.RotationPeriod = in.RotationPeriod
+	out.GracePeriod = in.GracePeriod
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaApplicationCredential.
+func (in *OctaviaApplicationCredential) DeepCopy() *OctaviaApplicationCredential {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaApplicationCredential)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaAutoscaling) DeepCopyInto(out *OctaviaAutoscaling) {
+	*out = *in
+	if in.TargetCPUUtilization != nil {
+		in, out := &in.TargetCPUUtilization, &out.TargetCPUUtilization
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetRequestsPerSecond != nil {
+		in, out := &in.TargetRequestsPerSecond, &out.TargetRequestsPerSecond
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaAutoscaling.
+func (in *OctaviaAutoscaling) DeepCopy() *OctaviaAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaControllerWorker) DeepCopyInto(out *OctaviaControllerWorker) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaControllerWorker.
+func (in *OctaviaControllerWorker) DeepCopy() *OctaviaControllerWorker {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaControllerWorker)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaDBBackup) DeepCopyInto(out *OctaviaDBBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaDBBackup.
+func (in *OctaviaDBBackup) DeepCopy() *OctaviaDBBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaDBBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OctaviaDBBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaDBBackupList) DeepCopyInto(out *OctaviaDBBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OctaviaDBBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaDBBackupList.
+func (in *OctaviaDBBackupList) DeepCopy() *OctaviaDBBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaDBBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OctaviaDBBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaDBBackupObjectStore) DeepCopyInto(out *OctaviaDBBackupObjectStore) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaDBBackupObjectStore.
+func (in *OctaviaDBBackupObjectStore) DeepCopy() *OctaviaDBBackupObjectStore {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaDBBackupObjectStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaDBBackupSpec) DeepCopyInto(out *OctaviaDBBackupSpec) {
+	*out = *in
+	in.Target.DeepCopyInto(&out.Target)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaDBBackupSpec.
+func (in *OctaviaDBBackupSpec) DeepCopy() *OctaviaDBBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaDBBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaDBBackupStatus) DeepCopyInto(out *OctaviaDBBackupStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(condition.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaDBBackupStatus.
+func (in *OctaviaDBBackupStatus) DeepCopy() *OctaviaDBBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaDBBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaDBBackupTarget) DeepCopyInto(out *OctaviaDBBackupTarget) {
+	*out = *in
+	if in.ObjectStore != nil {
+		in, out := &in.ObjectStore, &out.ObjectStore
+		*out = new(OctaviaDBBackupObjectStore)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaDBBackupTarget.
+func (in *OctaviaDBBackupTarget) DeepCopy() *OctaviaDBBackupTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaDBBackupTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaDBPurge) DeepCopyInto(out *OctaviaDBPurge) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaDBPurge.
+func (in *OctaviaDBPurge) DeepCopy() *OctaviaDBPurge {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaDBPurge)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaDBRestore) DeepCopyInto(out *OctaviaDBRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaDBRestore.
+func (in *OctaviaDBRestore) DeepCopy() *OctaviaDBRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaDBRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OctaviaDBRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaDBRestoreList) DeepCopyInto(out *OctaviaDBRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OctaviaDBRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaDBRestoreList.
+func (in *OctaviaDBRestoreList) DeepCopy() *OctaviaDBRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaDBRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OctaviaDBRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaDBRestoreSpec) DeepCopyInto(out *OctaviaDBRestoreSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaDBRestoreSpec.
+func (in *OctaviaDBRestoreSpec) DeepCopy() *OctaviaDBRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaDBRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaDBRestoreStatus) DeepCopyInto(out *OctaviaDBRestoreStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(condition.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaDBRestoreStatus.
+func (in *OctaviaDBRestoreStatus) DeepCopy() *OctaviaDBRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaDBRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaDBSyncJob) DeepCopyInto(out *OctaviaDBSyncJob) {
+	*out = *in
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaDBSyncJob.
+func (in *OctaviaDBSyncJob) DeepCopy() *OctaviaDBSyncJob {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaDBSyncJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaExporter) DeepCopyInto(out *OctaviaExporter) {
+	*out = *in
+	out.PollInterval = in.PollInterval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaExporter.
+func (in *OctaviaExporter) DeepCopy() *OctaviaExporter {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaExporter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaFlavor) DeepCopyInto(out *OctaviaFlavor) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaFlavor.
+func (in *OctaviaFlavor) DeepCopy() *OctaviaFlavor {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaFlavor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OctaviaFlavor) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaFlavorList) DeepCopyInto(out *OctaviaFlavorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OctaviaFlavor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaFlavorList.
+func (in *OctaviaFlavorList) DeepCopy() *OctaviaFlavorList {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaFlavorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OctaviaFlavorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaFlavorProfile) DeepCopyInto(out *OctaviaFlavorProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaFlavorProfile.
+func (in *OctaviaFlavorProfile) DeepCopy() *OctaviaFlavorProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaFlavorProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OctaviaFlavorProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaFlavorProfileList) DeepCopyInto(out *OctaviaFlavorProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OctaviaFlavorProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaFlavorProfileList.
+func (in *OctaviaFlavorProfileList) DeepCopy() *OctaviaFlavorProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaFlavorProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OctaviaFlavorProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaFlavorProfileSpec) DeepCopyInto(out *OctaviaFlavorProfileSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaFlavorProfileSpec.
+func (in *OctaviaFlavorProfileSpec) DeepCopy() *OctaviaFlavorProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaFlavorProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaFlavorProfileStatus) DeepCopyInto(out *OctaviaFlavorProfileStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(condition.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaFlavorProfileStatus.
+func (in *OctaviaFlavorProfileStatus) DeepCopy() *OctaviaFlavorProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaFlavorProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaFlavorSpec) DeepCopyInto(out *OctaviaFlavorSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaFlavorSpec.
+func (in *OctaviaFlavorSpec) DeepCopy() *OctaviaFlavorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaFlavorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaFlavorStatus) DeepCopyInto(out *OctaviaFlavorStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(condition.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaFlavorStatus.
+func (in *OctaviaFlavorStatus) DeepCopy() *OctaviaFlavorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaFlavorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaHAProxyAmphora) DeepCopyInto(out *OctaviaHAProxyAmphora) {
+	*out = *in
+	if in.TimeoutClientData != nil {
+		in, out := &in.TimeoutClientData, &out.TimeoutClientData
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutMemberConnect != nil {
+		in, out := &in.TimeoutMemberConnect, &out.TimeoutMemberConnect
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutMemberData != nil {
+		in, out := &in.TimeoutMemberData, &out.TimeoutMemberData
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutTCPInspect != nil {
+		in, out := &in.TimeoutTCPInspect, &out.TimeoutTCPInspect
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaHAProxyAmphora.
+func (in *OctaviaHAProxyAmphora) DeepCopy() *OctaviaHAProxyAmphora {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaHAProxyAmphora)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaNova) DeepCopyInto(out *OctaviaNova) {
+	*out = *in
+	if in.EnableAntiAffinity != nil {
+		in, out := &in.EnableAntiAffinity, &out.EnableAntiAffinity
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaNova.
+func (in *OctaviaNova) DeepCopy() *OctaviaNova {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaNova)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaOverride) DeepCopyInto(out *OctaviaOverride) {
+	*out = *in
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = make(map[endpoint.Endpoint]OctaviaServiceOverride, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	in.PublicRoute.DeepCopyInto(&out.PublicRoute)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaOverride.
+func (in *OctaviaOverride) DeepCopy() *OctaviaOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaPreUpgradeBackup) DeepCopyInto(out *OctaviaPreUpgradeBackup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaPreUpgradeBackup.
+func (in *OctaviaPreUpgradeBackup) DeepCopy() *OctaviaPreUpgradeBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaPreUpgradeBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaPreUpgradeBackupStatus) DeepCopyInto(out *OctaviaPreUpgradeBackupStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaPreUpgradeBackupStatus.
+func (in *OctaviaPreUpgradeBackupStatus) DeepCopy() *OctaviaPreUpgradeBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaPreUpgradeBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaRouteOverride) DeepCopyInto(out *OctaviaRouteOverride) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaRouteOverride.
+func (in *OctaviaRouteOverride) DeepCopy() *OctaviaRouteOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaRouteOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OctaviaServiceOverride) DeepCopyInto(out *OctaviaServiceOverride) {
+	*out = *in
+	if in.IPFamilyPolicy != nil {
+		in, out := &in.IPFamilyPolicy, &out.IPFamilyPolicy
+		*out = new(v1.IPFamilyPolicy)
+		**out = **in
+	}
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]v1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OctaviaServiceOverride.
+func (in *OctaviaServiceOverride) DeepCopy() *OctaviaServiceOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(OctaviaServiceOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PasswordSelector) DeepCopyInto(out *PasswordSelector) {
 	*out = *in
@@ -0,0 +1,97 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// octavia-exporter polls the load balancer, listener and amphora statistics from the Octavia
+// API and exposes them as Prometheus metrics. It authenticates against keystone using the
+// standard OS_* environment variables.
+package main
+
+import (
+	"flag"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/exporter"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func main() {
+	var listenAddress, octaviaURL string
+	var pollInterval time.Duration
+	flag.StringVar(&listenAddress, "listen-address", ":9120", "The address the metrics endpoint binds to.")
+	flag.StringVar(&octaviaURL, "octavia-url", os.Getenv("OCTAVIA_URL"), "URL of the Octavia API, defaults to $OCTAVIA_URL.")
+	flag.DurationVar(&pollInterval, "poll-interval", time.Minute, "Interval to poll the statistics from the Octavia API.")
+	opts := zap.Options{}
+	opts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	log := ctrl.Log.WithName("octavia-exporter")
+
+	if octaviaURL == "" {
+		log.Info("-octavia-url or OCTAVIA_URL is required")
+		os.Exit(2)
+	}
+
+	authOpts, err := openstack.AuthOptionsFromEnv()
+	if err != nil {
+		log.Error(err, "unable to get the keystone credentials from the environment")
+		os.Exit(1)
+	}
+	// the token expires while the exporter is running
+	authOpts.AllowReauth = true
+
+	providerClient, err := openstack.AuthenticatedClient(authOpts)
+	if err != nil {
+		log.Error(err, "unable to authenticate against keystone")
+		os.Exit(1)
+	}
+
+	e := exporter.NewExporter(octavia.NewClient(providerClient, octaviaURL), log)
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(e)
+
+	ctx := ctrl.SetupSignalHandler()
+	go e.Run(ctx, pollInterval)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server := &http.Server{
+		Addr:              listenAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	log.Info("serving metrics", "address", listenAddress, "octaviaURL", octaviaURL)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Error(err, "unable to serve the metrics")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	structuraldefaulting "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/defaulting"
+	"sigs.k8s.io/yaml"
+)
+
+// readOctaviaAPI - reads the OctaviaAPI and applies the defaults of the CRD schema like the
+// API server does, to render the config of the object the controller would get
+func readOctaviaAPI(file string, crdFile string) (*octaviav1.OctaviaAPI, error) {
+	schema, err := readStructuralSchema(crdFile, octaviav1.GroupVersion.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	obj := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	structuraldefaulting.Default(obj, schema)
+
+	defaulted, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	instance := &octaviav1.OctaviaAPI{}
+	if err := json.Unmarshal(defaulted, instance); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return instance, nil
+}
+
+// readStructuralSchema - returns the structural schema of version of the CRD
+func readStructuralSchema(crdFile string, version string) (*structuralschema.Structural, error) {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := readYAML(crdFile, crd); err != nil {
+		return nil, err
+	}
+
+	for _, v := range crd.Spec.Versions {
+		if v.Name != version || v.Schema == nil || v.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+		props := &apiextensions.JSONSchemaProps{}
+		if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(v.Schema.OpenAPIV3Schema, props, nil); err != nil {
+			return nil, fmt.Errorf("%s: %w", crdFile, err)
+		}
+		schema, err := structuralschema.NewStructural(props)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", crdFile, err)
+		}
+		return schema, nil
+	}
+	return nil, fmt.Errorf("%s: no schema for version %s", crdFile, version)
+}
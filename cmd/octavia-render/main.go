@@ -0,0 +1,211 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// octavia-render renders the config files of an OctaviaAPI without a cluster. It uses the
+// same templates as the operator and merges them like the init container of the pods does,
+// so the resulting files can be reviewed and diffed.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	keystonev1 "github.com/openstack-k8s-operators/keystone-operator/api/v1beta1"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func main() {
+	var octaviaAPIFile, crdFile, secretFile, keystoneAPIFile, baseConfigFile string
+	var templatesDir, outputDir, nbConnection, sbConnection string
+	var memcachedServers string
+	var ipv6 bool
+	flag.StringVar(&octaviaAPIFile, "octavia-api", "", "OctaviaAPI YAML file to render the config for.")
+	flag.StringVar(&crdFile, "crd", "config/crd/bases/octavia.openstack.org_octaviaapis.yaml", "OctaviaAPI CRD providing the defaults of the spec.")
+	flag.StringVar(&secretFile, "secret", "", "Optional Secret YAML file holding the passwords referenced by the OctaviaAPI.")
+	flag.StringVar(&keystoneAPIFile, "keystone-api", "", "Optional KeystoneAPI YAML file, with status, providing the keystone endpoints. A placeholder gets rendered without it.")
+	flag.StringVar(&baseConfigFile, "base-config", "", "Optional octavia.conf of the container image the rendered config gets merged into.")
+	flag.StringVar(&nbConnection, "ovn-nb-connection", "", "OVN NB DB connection, used if the ovn provider is enabled.")
+	flag.StringVar(&sbConnection, "ovn-sb-connection", "", "OVN SB DB connection, used if the ovn provider is enabled.")
+	flag.StringVar(&memcachedServers, "memcached-servers", "", "Memcached servers, used if spec.memcachedInstance is set.")
+	flag.BoolVar(&ipv6, "ipv6", false, "Render the config for a cluster with IPv6 as primary service network family.")
+	flag.StringVar(&templatesDir, "templates", "templates", "Directory holding the operator templates.")
+	flag.StringVar(&outputDir, "output", "", "Directory the merged config files get written to.")
+	flag.Parse()
+
+	if octaviaAPIFile == "" || outputDir == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := render(octaviaAPIFile, crdFile, secretFile, keystoneAPIFile, baseConfigFile,
+		templatesDir, outputDir, nbConnection, sbConnection, memcachedServers, ipv6); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func render(
+	octaviaAPIFile string,
+	crdFile string,
+	secretFile string,
+	keystoneAPIFile string,
+	baseConfigFile string,
+	templatesDir string,
+	outputDir string,
+	nbConnection string,
+	sbConnection string,
+	memcachedServers string,
+	ipv6 bool,
+) error {
+	instance, err := readOctaviaAPI(octaviaAPIFile, crdFile)
+	if err != nil {
+		return err
+	}
+	if instance.Kind == "" {
+		instance.Kind = "OctaviaAPI"
+	}
+
+	if err := octavia.ValidateEndpoints(instance); err != nil {
+		return err
+	}
+
+	// the template parameters the controller passes to generateServiceConfigMaps
+	templateParameters := map[string]interface{}{}
+	clusterFamilies := []corev1.IPFamily{corev1.IPv4Protocol}
+	if ipv6 {
+		clusterFamilies = []corev1.IPFamily{corev1.IPv6Protocol}
+	}
+	templateParameters["BindHost"] = octavia.GetBindHost(octavia.GetEndpointIPFamilies(instance, clusterFamilies))
+	if instance.IsProviderEnabled(octaviav1.ProviderDriverOVN) {
+		templateParameters["NBConnection"] = nbConnection
+		templateParameters["SBConnection"] = sbConnection
+	}
+	if instance.Spec.MemcachedInstance != "" {
+		templateParameters["MemcachedServers"] = memcachedServers
+	}
+	templateParameters["KeystonePublicURL"] = "<KeystonePublicURL>"
+	if keystoneAPIFile != "" {
+		keystoneAPI := &keystonev1.KeystoneAPI{}
+		if err := readYAML(keystoneAPIFile, keystoneAPI); err != nil {
+			return err
+		}
+		keystoneParameters, err := octavia.GetKeystoneParameters(keystoneAPI)
+		if err != nil {
+			return fmt.Errorf("%s: %w", keystoneAPIFile, err)
+		}
+		for key, value := range keystoneParameters {
+			templateParameters[key] = value
+		}
+	}
+	for key, value := range octavia.GetServiceConfigParameters(instance) {
+		templateParameters[key] = value
+	}
+
+	templates, err := filepath.Abs(templatesDir)
+	if err != nil {
+		return err
+	}
+	// used by the lib-common template functions
+	if err := os.Setenv("OPERATOR_TEMPLATES", templates+"/"); err != nil {
+		return err
+	}
+
+	// the config-data configmap, the scripts are not part of the service config
+	cms := octavia.ServiceConfigTemplates(instance, map[string]string{}, templateParameters)
+	configData, err := octavia.RenderTemplate(cms[1])
+	if err != nil {
+		return err
+	}
+
+	//
+	// merge like init.sh: start from the octavia.conf of the image, merge the
+	// config-data files and set the database connection
+	//
+	merged := map[string]string{}
+	if baseConfigFile != "" {
+		base, err := os.ReadFile(baseConfigFile)
+		if err != nil {
+			return err
+		}
+		merged["octavia.conf"] = string(base)
+	}
+	octavia.MergeConfigDir(merged, configData)
+
+	dbPassword, err := getDatabasePassword(secretFile, instance)
+	if err != nil {
+		return err
+	}
+	dbHost := instance.Status.DatabaseHostname
+	if dbHost == "" {
+		dbHost = "<DatabaseHost>"
+	}
+	conf := octavia.ParseIni(merged["octavia.conf"])
+	conf.Set("database", "connection",
+		octavia.DatabaseConnection(instance.Spec.DatabaseUser, dbPassword, dbHost, octavia.DatabaseName))
+	merged["octavia.conf"] = conf.String()
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+	for name, data := range merged {
+		if err := os.WriteFile(filepath.Join(outputDir, name), []byte(data), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getDatabasePassword - returns the DB password from the secret, or a placeholder if no
+// secret was passed to not require real passwords for reviewing the config
+func getDatabasePassword(secretFile string, instance *octaviav1.OctaviaAPI) (string, error) {
+	selector := instance.Spec.PasswordSelectors.Database
+	if selector == "" {
+		selector = "OctaviaDatabasePassword"
+	}
+	if secretFile == "" {
+		return "<" + selector + ">", nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := readYAML(secretFile, secret); err != nil {
+		return "", err
+	}
+	if password, ok := secret.StringData[selector]; ok {
+		return password, nil
+	}
+	if password, ok := secret.Data[selector]; ok {
+		return string(password), nil
+	}
+	return "", fmt.Errorf("%s: key %s not found in secret", secretFile, selector)
+}
+
+func readYAML(file string, obj interface{}) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(data, obj); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	keystonev1 "github.com/openstack-k8s-operators/keystone-operator/api/v1beta1"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	sampleFile   = "../../config/samples/octavia_v1beta1_octaviaapi.yaml"
+	crdFile      = "../../config/crd/bases/octavia.openstack.org_octaviaapis.yaml"
+	templatesDir = "../../templates"
+	keystoneFile = "testdata/keystoneapi.yaml"
+)
+
+// controllerConfig - returns the config files the controller generates for the OctaviaAPI,
+// merged like the init container does
+func controllerConfig(t *testing.T, instance *octaviav1.OctaviaAPI, keystoneAPI *keystonev1.KeystoneAPI) map[string]string {
+	t.Helper()
+
+	templates, err := filepath.Abs(templatesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("OPERATOR_TEMPLATES", templates+"/")
+
+	// like generateServiceConfigMaps on a cluster with an IPv4 service network
+	templateParameters, err := octavia.GetKeystoneParameters(keystoneAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clusterFamilies := []corev1.IPFamily{corev1.IPv4Protocol}
+	templateParameters["BindHost"] = octavia.GetBindHost(octavia.GetEndpointIPFamilies(instance, clusterFamilies))
+	for key, value := range octavia.GetServiceConfigParameters(instance) {
+		templateParameters[key] = value
+	}
+	cms := octavia.ServiceConfigTemplates(instance, map[string]string{}, templateParameters)
+	configData, err := octavia.RenderTemplate(cms[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged := map[string]string{}
+	octavia.MergeConfigDir(merged, configData)
+	conf := octavia.ParseIni(merged["octavia.conf"])
+	conf.Set("database", "connection",
+		octavia.DatabaseConnection(instance.Spec.DatabaseUser, "<OctaviaDatabasePassword>", "<DatabaseHost>", octavia.DatabaseName))
+	merged["octavia.conf"] = conf.String()
+	return merged
+}
+
+func readRendered(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[e.Name()] = string(data)
+	}
+	return files
+}
+
+func TestRenderSample(t *testing.T) {
+	output := t.TempDir()
+	if err := render(sampleFile, crdFile, "", keystoneFile, "", templatesDir, output, "", "", "", false); err != nil {
+		t.Fatal(err)
+	}
+	rendered := readRendered(t, output)
+
+	// the defaults of the CRD are applied like on the stored object
+	for _, option := range []string{
+		"enabled_provider_drivers=amphora",
+		"allow_tls_terminated_listeners=true",
+		"timeout_client_data=50000",
+		"enable_anti_affinity=true",
+		"auth_url=http://keystone-public-openstack.apps-crc.testing/v3",
+	} {
+		if !strings.Contains(rendered["octavia.conf"], option) {
+			t.Errorf("octavia.conf does not contain %s", option)
+		}
+	}
+
+	instance, err := readOctaviaAPI(sampleFile, crdFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	instance.Kind = "OctaviaAPI"
+	keystoneAPI := &keystonev1.KeystoneAPI{}
+	if err := readYAML(keystoneFile, keystoneAPI); err != nil {
+		t.Fatal(err)
+	}
+	expected := controllerConfig(t, instance, keystoneAPI)
+
+	if len(rendered) != len(expected) {
+		t.Errorf("expected the files %v, got %v", keys(expected), keys(rendered))
+	}
+	for name, data := range expected {
+		if rendered[name] != data {
+			t.Errorf("%s differs from the controller config:\nexpected:\n%s\nactual:\n%s", name, data, rendered[name])
+		}
+	}
+}
+
+func TestRenderWithoutKeystone(t *testing.T) {
+	output := t.TempDir()
+	if err := render(sampleFile, crdFile, "", "", "", templatesDir, output, "", "", "", false); err != nil {
+		t.Fatal(err)
+	}
+	conf := readRendered(t, output)["octavia.conf"]
+
+	if !strings.Contains(conf, "auth_url=<KeystonePublicURL>/v3") {
+		t.Errorf("expected the keystone placeholder in:\n%s", conf)
+	}
+	if strings.Contains(conf, "<no value>") {
+		t.Errorf("unset template parameter in:\n%s", conf)
+	}
+}
+
+func keys(m map[string]string) []string {
+	k := make([]string, 0, len(m))
+	for name := range m {
+		k = append(k, name)
+	}
+	return k
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	keystonev1 "github.com/openstack-k8s-operators/keystone-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	oko_secret "github.com/openstack-k8s-operators/lib-common/modules/common/secret"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia"
+
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+//
+// reconcileApplicationCredential - creates the application credential of the service user, rotates it
+// after the rotation period and removes the previous one once the grace period passed. Returns the hash
+// of the credential, which restarts the API pods on a rotation, and when the next rotation step is due.
+// Authenticates as the service user with password at authURL.
+//
+func (r *OctaviaAPIReconciler) reconcileApplicationCredential(
+	ctx context.Context,
+	instance *octaviav1.OctaviaAPI,
+	h *helper.Helper,
+	labels map[string]string,
+	authURL string,
+	password string,
+) (string, ctrl.Result, error) {
+	current := &corev1.Secret{}
+	err := r.Client.Get(ctx, types.NamespacedName{
+		Name:      octavia.ApplicationCredentialSecretName(instance),
+		Namespace: instance.Namespace,
+	}, current)
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return "", ctrl.Result{}, err
+	}
+	state := octavia.GetApplicationCredentialState(current)
+
+	if !instance.Spec.ApplicationCredential.Enabled {
+		removeCondition(&instance.Status.Conditions, octaviav1.ApplicationCredentialReadyCondition)
+		instance.Status.ApplicationCredentialID = ""
+		return "", ctrl.Result{}, r.deleteApplicationCredential(ctx, instance, current, state, authURL, password)
+	}
+
+	now := time.Now()
+	rotate, removePrevious, next := octavia.ApplicationCredentialRotation(
+		instance.Spec.ApplicationCredential, state, now)
+
+	if rotate || removePrevious {
+		identityClient, err := octavia.NewIdentityClient(authURL, instance.Spec.ServiceUser, password)
+		if err != nil {
+			return "", ctrl.Result{}, r.setApplicationCredentialError(instance, err)
+		}
+
+		if rotate {
+			// a previous credential still in its grace period is no longer needed
+			if state.PreviousID != "" {
+				if err := identityClient.DeleteApplicationCredential(state.PreviousID); err != nil {
+					return "", ctrl.Result{}, r.setApplicationCredentialError(instance, err)
+				}
+			}
+
+			spec := instance.Spec.ApplicationCredential
+			expiresAt := now.Add(spec.RotationPeriod.Duration + spec.GracePeriod.Duration)
+			id, secret, err := identityClient.CreateApplicationCredential(
+				fmt.Sprintf("%s-%s", instance.Name, now.UTC().Format("20060102150405")),
+				spec.Roles,
+				expiresAt)
+			if err != nil {
+				return "", ctrl.Result{}, r.setApplicationCredentialError(instance, err)
+			}
+			r.Log.Info(fmt.Sprintf("Created application credential %s, previous %s", id, state.ID))
+
+			appCredSecret := octavia.ApplicationCredentialSecret(instance, labels, id, secret, now, state.ID)
+			if err := r.ensureApplicationCredentialSecret(ctx, instance, h, appCredSecret); err != nil {
+				// without the secret the credential would be lost, the next reconcile creates a new one
+				if delErr := identityClient.DeleteApplicationCredential(id); delErr != nil {
+					r.Log.Error(delErr, fmt.Sprintf("Failed to remove the unsaved application credential %s", id))
+				}
+				return "", ctrl.Result{}, r.setApplicationCredentialError(instance, err)
+			}
+			current = appCredSecret
+			// the previous credential gets removed after the grace period, before the next rotation
+			_, _, next = octavia.ApplicationCredentialRotation(
+				spec, octavia.GetApplicationCredentialState(appCredSecret), now)
+		} else {
+			if err := identityClient.DeleteApplicationCredential(state.PreviousID); err != nil {
+				return "", ctrl.Result{}, r.setApplicationCredentialError(instance, err)
+			}
+			r.Log.Info(fmt.Sprintf("Removed previous application credential %s", state.PreviousID))
+
+			appCredSecret := octavia.ApplicationCredentialSecret(instance, labels,
+				state.ID, string(current.Data[octavia.ApplicationCredentialSecretKey]), state.CreatedAt, "")
+			if err := r.ensureApplicationCredentialSecret(ctx, instance, h, appCredSecret); err != nil {
+				return "", ctrl.Result{}, r.setApplicationCredentialError(instance, err)
+			}
+			current = appCredSecret
+		}
+	}
+
+	// only the credential itself restarts the pods, not the bookkeeping annotations
+	hash, err := util.ObjectHash(current.Data)
+	if err != nil {
+		return "", ctrl.Result{}, err
+	}
+
+	instance.Status.ApplicationCredentialID = string(current.Data[octavia.ApplicationCredentialIDKey])
+	instance.Status.Conditions.MarkTrue(
+		octaviav1.ApplicationCredentialReadyCondition,
+		octaviav1.ApplicationCredentialReadyMessage)
+
+	return hash, ctrl.Result{RequeueAfter: next}, nil
+}
+
+// setApplicationCredentialError - marks the ApplicationCredentialReady condition failed with err
+func (r *OctaviaAPIReconciler) setApplicationCredentialError(instance *octaviav1.OctaviaAPI, err error) error {
+	instance.Status.Conditions.Set(condition.FalseCondition(
+		octaviav1.ApplicationCredentialReadyCondition,
+		condition.ErrorReason,
+		condition.SeverityWarning,
+		octaviav1.ApplicationCredentialReadyErrorMessage,
+		err.Error()))
+	return err
+}
+
+// ensureApplicationCredentialSecret - creates or updates the secret holding the application credential
+func (r *OctaviaAPIReconciler) ensureApplicationCredentialSecret(
+	ctx context.Context,
+	instance *octaviav1.OctaviaAPI,
+	h *helper.Helper,
+	secret *corev1.Secret,
+) error {
+	current := &corev1.Secret{}
+	current.Name = secret.Name
+	current.Namespace = secret.Namespace
+
+	_, err := controllerutil.CreateOrPatch(ctx, r.Client, current, func() error {
+		current.Labels = util.MergeStringMaps(current.Labels, secret.Labels)
+		current.Annotations = secret.Annotations
+		current.Type = secret.Type
+		current.Data = secret.Data
+		return controllerutil.SetControllerReference(instance, current, h.GetScheme())
+	})
+	return err
+}
+
+// revokeApplicationCredential - removes the application credentials of the deleted instance from
+// keystone. Skips the removal if keystone or the password of the service user are gone, the
+// credentials expire on their own then.
+func (r *OctaviaAPIReconciler) revokeApplicationCredential(
+	ctx context.Context,
+	instance *octaviav1.OctaviaAPI,
+	h *helper.Helper,
+) error {
+	current := &corev1.Secret{}
+	err := r.Client.Get(ctx, types.NamespacedName{
+		Name:      octavia.ApplicationCredentialSecretName(instance),
+		Namespace: instance.Namespace,
+	}, current)
+	if k8s_errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	state := octavia.GetApplicationCredentialState(current)
+
+	keystoneAPI, err := keystonev1.GetKeystoneAPI(ctx, h, instance.Namespace, map[string]string{})
+	if k8s_errors.IsNotFound(err) {
+		r.Log.Info(fmt.Sprintf("KeystoneAPI not found, application credential %s not removed", state.ID))
+		return nil
+	} else if err != nil {
+		return err
+	}
+	authURL, err := keystoneAPI.GetEndpoint(endpoint.EndpointPublic)
+	if err != nil {
+		r.Log.Info(fmt.Sprintf("KeystoneAPI not available, application credential %s not removed", state.ID))
+		return nil
+	}
+
+	ospSecret, _, err := oko_secret.GetSecret(ctx, h, instance.Spec.Secret, instance.Namespace)
+	if k8s_errors.IsNotFound(err) {
+		r.Log.Info(fmt.Sprintf("OpenStack secret not found, application credential %s not removed", state.ID))
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return r.deleteApplicationCredential(ctx, instance, current, state, authURL,
+		string(ospSecret.Data[instance.Spec.PasswordSelectors.Service]))
+}
+
+// deleteApplicationCredential - removes the application credentials of the secret from keystone
+// and the secret, after the application credential got disabled or the instance deleted
+func (r *OctaviaAPIReconciler) deleteApplicationCredential(
+	ctx context.Context,
+	instance *octaviav1.OctaviaAPI,
+	current *corev1.Secret,
+	state octavia.ApplicationCredentialState,
+	authURL string,
+	password string,
+) error {
+	if current.Name == "" {
+		return nil
+	}
+
+	identityClient, err := octavia.NewIdentityClient(authURL, instance.Spec.ServiceUser, password)
+	if err != nil {
+		return err
+	}
+	for _, id := range []string{state.ID, state.PreviousID} {
+		if id == "" {
+			continue
+		}
+		if err := identityClient.DeleteApplicationCredential(id); err != nil {
+			return err
+		}
+	}
+
+	if err := r.Client.Delete(ctx, current); err != nil && !k8s_errors.IsNotFound(err) {
+		return err
+	}
+	r.Log.Info(fmt.Sprintf("Removed application credential %s", state.ID))
+	return nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// getOctaviaAPIWithDB - returns the OctaviaAPI deployed in the namespace once its DB got synced.
+// The OctaviaAPIReadyCondition of the passed conditions gets updated accordingly.
+func getOctaviaAPIWithDB(
+	ctx context.Context,
+	h *helper.Helper,
+	namespace string,
+	conditions *condition.Conditions,
+) (*octaviav1.OctaviaAPI, ctrl.Result, error) {
+	octaviaAPI, err := octaviav1.GetOctaviaAPI(ctx, h, namespace, map[string]string{})
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			conditions.Set(condition.FalseCondition(
+				octaviav1.OctaviaAPIReadyCondition,
+				condition.RequestedReason,
+				condition.SeverityInfo,
+				octaviav1.OctaviaAPIReadyNotFoundMessage))
+			return nil, ctrl.Result{RequeueAfter: octaviaClientRequeue}, nil
+		}
+		conditions.Set(condition.FalseCondition(
+			octaviav1.OctaviaAPIReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			octaviav1.OctaviaAPIReadyErrorMessage,
+			err.Error()))
+		return nil, ctrl.Result{}, err
+	}
+
+	if octaviaAPI.Status.DatabaseHostname == "" ||
+		!octaviaAPI.Status.Conditions.IsTrue(condition.DBSyncReadyCondition) {
+		conditions.Set(condition.FalseCondition(
+			octaviav1.OctaviaAPIReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			octaviav1.OctaviaAPIReadyWaitingMessage))
+		return nil, ctrl.Result{RequeueAfter: octaviaClientRequeue}, nil
+	}
+
+	conditions.MarkTrue(octaviav1.OctaviaAPIReadyCondition, octaviav1.OctaviaAPIReadyMessage)
+
+	return octaviaAPI, ctrl.Result{}, nil
+}
+
+// ensureOneShotJob - creates the job owned by owner if it does not exist yet, and returns the
+// current job. The job does not get updated, it runs once.
+func ensureOneShotJob(
+	ctx context.Context,
+	h *helper.Helper,
+	owner client.Object,
+	desired *batchv1.Job,
+) (*batchv1.Job, error) {
+	current := &batchv1.Job{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, current)
+	if err == nil {
+		return current, nil
+	}
+	if !k8s_errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	if err := controllerutil.SetControllerReference(owner, desired, h.GetScheme()); err != nil {
+		return nil, err
+	}
+	if err := h.GetClient().Create(ctx, desired); err != nil {
+		return nil, err
+	}
+	return desired, nil
+}
+
+// getJobResult - returns if the job finished, and the reason if it failed
+func getJobResult(job *batchv1.Job) (finished bool, failure string) {
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return true, ""
+		case batchv1.JobFailed:
+			return true, c.Reason + ": " + c.Message
+		}
+	}
+	return false, ""
+}
+
+// deleteFinishedJob - removes the finished job including its pods, unless jobs get preserved
+func deleteFinishedJob(ctx context.Context, h *helper.Helper, job *batchv1.Job, preserveJobs bool) error {
+	if preserveJobs {
+		return nil
+	}
+	err := h.GetClient().Delete(ctx, job, client.PropagationPolicy("Background"))
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
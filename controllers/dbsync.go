@@ -0,0 +1,98 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// jobLogTailLines - number of log lines of a failed job reported in the condition
+	jobLogTailLines = 10
+	// jobLogLimitBytes - limits the reported log lines of a failed job to keep the condition small
+	jobLogLimitBytes = 2048
+)
+
+// getJobLogTail - returns the last log lines of the container which failed in the last failed pod
+// of the job, prefixed with the pod and container name. Returns an empty string if there is no
+// failed pod (anymore).
+func getJobLogTail(ctx context.Context, kclient kubernetes.Interface, job *batchv1.Job) (string, error) {
+	if job.Spec.Selector == nil {
+		return "", nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+	if err != nil {
+		return "", err
+	}
+	pods, err := kclient.CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var failedPod *corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodFailed {
+			continue
+		}
+		if failedPod == nil || failedPod.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			failedPod = pod
+		}
+	}
+	if failedPod == nil {
+		return "", nil
+	}
+
+	container := getFailedContainer(failedPod)
+	if container == "" && len(job.Spec.Template.Spec.Containers) > 0 {
+		container = job.Spec.Template.Spec.Containers[0].Name
+	}
+	tailLines := int64(jobLogTailLines)
+	limitBytes := int64(jobLogLimitBytes)
+	logs, err := kclient.CoreV1().Pods(failedPod.Namespace).GetLogs(failedPod.Name, &corev1.PodLogOptions{
+		Container:  container,
+		TailLines:  &tailLines,
+		LimitBytes: &limitBytes,
+	}).DoRaw(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("pod %s container %s:\n%s", failedPod.Name, container,
+		strings.TrimRight(string(logs), "\n")), nil
+}
+
+// getFailedContainer - returns the first init or app container of the pod which exited with an error
+func getFailedContainer(pod *corev1.Pod) string {
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.State.Terminated != nil && status.State.Terminated.ExitCode != 0 {
+			return status.Name
+		}
+	}
+	return ""
+}
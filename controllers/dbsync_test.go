@@ -0,0 +1,93 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetJobLogTail(t *testing.T) {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "octavia-db-sync", Namespace: "openstack"},
+		Spec: batchv1.JobSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"job-name": "octavia-db-sync"}},
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "octavia-db-sync"}},
+				},
+			},
+		},
+	}
+	created := time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC)
+	pod := func(name string, phase corev1.PodPhase, age time.Duration, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "openstack",
+				Labels:            labels,
+				CreationTimestamp: metav1.NewTime(created.Add(-age)),
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	jobLabels := map[string]string{"job-name": "octavia-db-sync"}
+
+	// the init container of the last failed pod failed
+	lastFailed := pod("octavia-db-sync-c", corev1.PodFailed, time.Minute, jobLabels)
+	lastFailed.Status.InitContainerStatuses = []corev1.ContainerStatus{
+		{Name: "init", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}}},
+	}
+	kclient := fake.NewSimpleClientset(
+		pod("octavia-db-sync-a", corev1.PodFailed, 3*time.Minute, jobLabels),
+		lastFailed,
+		pod("octavia-db-sync-d", corev1.PodRunning, 0, jobLabels),
+		pod("octavia-other", corev1.PodFailed, 0, map[string]string{"job-name": "octavia-other"}),
+	)
+
+	logs, err := getJobLogTail(context.TODO(), kclient, job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logs != "pod octavia-db-sync-c container init:\nfake logs" {
+		t.Errorf("unexpected logs %q", logs)
+	}
+
+	var logOptions *corev1.PodLogOptions
+	for _, action := range kclient.Actions() {
+		if action.GetSubresource() == "log" {
+			logOptions = action.(k8stesting.GenericAction).GetValue().(*corev1.PodLogOptions)
+		}
+	}
+	if logOptions == nil || *logOptions.TailLines != jobLogTailLines || logOptions.Container != "init" {
+		t.Errorf("unexpected log options %v", logOptions)
+	}
+
+	// without a failed pod there are no logs to report
+	logs, err = getJobLogTail(context.TODO(), fake.NewSimpleClientset(
+		pod("octavia-db-sync-d", corev1.PodRunning, 0, jobLabels)), job)
+	if err != nil || logs != "" {
+		t.Errorf("expected no logs, got %q, %v", logs, err)
+	}
+}
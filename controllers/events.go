@@ -0,0 +1,55 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+//
+// recordConditionEvents - emits an event for each condition which changed its status, reason or
+// message during the reconcile. As every reconcile phase reports its progress via a condition,
+// this results in one event per phase transition, while steady state reconciles emit none.
+//
+func recordConditionEvents(
+	recorder record.EventRecorder,
+	obj runtime.Object,
+	before condition.Conditions,
+	after condition.Conditions,
+) {
+	if recorder == nil {
+		return
+	}
+
+	for _, c := range after {
+		b := before.Get(c.Type)
+		if b != nil && b.Status == c.Status && b.Reason == c.Reason && b.Message == c.Message {
+			continue
+		}
+
+		eventType := corev1.EventTypeNormal
+		if c.Status == corev1.ConditionFalse &&
+			(c.Severity == condition.SeverityWarning || c.Severity == condition.SeverityError) {
+			eventType = corev1.EventTypeWarning
+		}
+		recorder.Event(obj, eventType, string(c.Type), c.Message)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	"k8s.io/client-go/tools/record"
+)
+
+func TestRecordConditionEvents(t *testing.T) {
+	before := condition.CreateList(
+		condition.TrueCondition(condition.InputReadyCondition, condition.InputReadyMessage),
+		condition.FalseCondition(condition.DBReadyCondition, condition.RequestedReason, condition.SeverityInfo, condition.DBReadyRunningMessage),
+		condition.FalseCondition(condition.DBSyncReadyCondition, condition.RequestedReason, condition.SeverityInfo, condition.DBSyncReadyRunningMessage),
+	)
+	after := condition.CreateList(
+		// unchanged
+		condition.TrueCondition(condition.InputReadyCondition, condition.InputReadyMessage),
+		// transitioned
+		condition.TrueCondition(condition.DBReadyCondition, condition.DBReadyMessage),
+		condition.FalseCondition(condition.DBSyncReadyCondition, condition.ErrorReason, condition.SeverityWarning, condition.DBSyncReadyErrorMessage, "failed"),
+	)
+
+	recorder := record.NewFakeRecorder(10)
+	recordConditionEvents(recorder, &octaviav1.OctaviaAPI{}, before, after)
+	close(recorder.Events)
+
+	events := []string{}
+	for e := range recorder.Events {
+		events = append(events, e)
+	}
+	expected := []string{
+		"Normal DBReady " + condition.DBReadyMessage,
+		"Warning DBSyncReady DBsync job error occured failed",
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("got events %v, want %v", events, expected)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("event %d = %q, want %q", i, events[i], expected[i])
+		}
+	}
+
+	// a steady state reconcile emits no events
+	recorder = record.NewFakeRecorder(10)
+	recordConditionEvents(recorder, &octaviav1.OctaviaAPI{}, after, after)
+	if len(recorder.Events) != 0 {
+		t.Errorf("got %d events for unchanged conditions", len(recorder.Events))
+	}
+}
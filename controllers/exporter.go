@@ -0,0 +1,196 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/deployment"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/service"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete;
+
+//
+// reconcileExporter - deploys the stats exporter, its service and ServiceMonitor if enabled,
+// otherwise removes them. The exporter authenticates as service user at authURL.
+//
+func (r *OctaviaAPIReconciler) reconcileExporter(
+	ctx context.Context,
+	instance *octaviav1.OctaviaAPI,
+	h *helper.Helper,
+	authURL string,
+) (ctrl.Result, error) {
+	if !instance.Spec.Exporter.Enabled {
+		removeCondition(&instance.Status.Conditions, octaviav1.ExporterReadyCondition)
+		return ctrl.Result{}, r.deleteExporter(ctx, instance)
+	}
+
+	internalURL, err := instance.GetEndpoint(endpoint.EndpointInternal)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.ExporterReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			octaviav1.ExporterReadyRunningMessage))
+		return ctrl.Result{}, nil
+	}
+
+	exporterLabels := octavia.ExporterLabels()
+
+	depl := deployment.NewDeployment(
+		octavia.ExporterDeployment(instance, exporterLabels, authURL, internalURL),
+		5,
+	)
+	ctrlResult, err := depl.CreateOrPatch(ctx, h)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.ExporterReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			octaviav1.ExporterReadyErrorMessage,
+			err.Error()))
+		return ctrlResult, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.ExporterReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			octaviav1.ExporterReadyRunningMessage))
+		return ctrlResult, nil
+	}
+
+	svc := service.NewService(octavia.ExporterService(instance, exporterLabels), exporterLabels, 5)
+	ctrlResult, err = svc.CreateOrPatch(ctx, h)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.ExporterReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			octaviav1.ExporterReadyErrorMessage,
+			err.Error()))
+		return ctrlResult, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+
+	err = r.ensureServiceMonitor(ctx, instance, h, octavia.ExporterServiceMonitor(instance, exporterLabels))
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.ExporterReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			octaviav1.ExporterReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+
+	exporterDeployment := depl.GetDeployment()
+	if octavia.IsDeploymentReady(&exporterDeployment) {
+		instance.Status.Conditions.MarkTrue(octaviav1.ExporterReadyCondition, octaviav1.ExporterReadyMessage)
+	} else {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.ExporterReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			octaviav1.ExporterReadyRunningMessage))
+	}
+
+	return ctrl.Result{}, nil
+}
+
+//
+// ensureServiceMonitor - creates or patches the ServiceMonitor. It gets skipped if the
+// prometheus-operator CRDs are not installed in the cluster.
+//
+func (r *OctaviaAPIReconciler) ensureServiceMonitor(
+	ctx context.Context,
+	instance *octaviav1.OctaviaAPI,
+	h *helper.Helper,
+	desired *unstructured.Unstructured,
+) error {
+	monitor := &unstructured.Unstructured{}
+	monitor.SetGroupVersionKind(desired.GroupVersionKind())
+	monitor.SetName(desired.GetName())
+	monitor.SetNamespace(desired.GetNamespace())
+
+	op, err := controllerutil.CreateOrPatch(ctx, r.Client, monitor, func() error {
+		monitor.SetLabels(util.MergeStringMaps(monitor.GetLabels(), desired.GetLabels()))
+		monitor.Object["spec"] = desired.Object["spec"]
+
+		return controllerutil.SetControllerReference(instance, monitor, r.Scheme)
+	})
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			util.LogForObject(h, "ServiceMonitor CRD not installed, skipping the ServiceMonitor of the exporter", instance)
+			return nil
+		}
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		util.LogForObject(h, fmt.Sprintf("ServiceMonitor %s - %s", monitor.GetName(), op), instance)
+	}
+
+	return nil
+}
+
+//
+// deleteExporter - deletes the exporter resources, e.g. after the exporter got disabled
+//
+func (r *OctaviaAPIReconciler) deleteExporter(
+	ctx context.Context,
+	instance *octaviav1.OctaviaAPI,
+) error {
+	objectMeta := metav1.ObjectMeta{
+		Name:      octavia.ExporterName,
+		Namespace: instance.Namespace,
+	}
+	monitor := &unstructured.Unstructured{}
+	monitor.SetGroupVersionKind(octavia.ServiceMonitorGVK)
+	monitor.SetName(objectMeta.Name)
+	monitor.SetNamespace(objectMeta.Namespace)
+
+	for _, obj := range []client.Object{
+		&appsv1.Deployment{ObjectMeta: objectMeta},
+		&corev1.Service{ObjectMeta: objectMeta},
+		monitor,
+	} {
+		err := r.Client.Delete(ctx, obj)
+		if err != nil && !k8s_errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/route"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/service"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia"
+
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+//
+// exposeEndpoints - creates the services and routes of the API endpoints with the overrides of
+// the spec applied, and returns the endpoint URLs. Routes which are no longer wanted get removed.
+//
+func (r *OctaviaAPIReconciler) exposeEndpoints(
+	ctx context.Context,
+	instance *octaviav1.OctaviaAPI,
+	h *helper.Helper,
+	selector map[string]string,
+) (map[string]string, ctrl.Result, error) {
+	endpointMap := make(map[string]string)
+
+	for _, endpointType := range octavia.Endpoints {
+		svcSpec := octavia.EndpointService(instance, endpointType, selector)
+		svc := service.NewService(svcSpec, svcSpec.Labels, 5)
+		ctrlResult, err := svc.CreateOrPatch(ctx, h)
+		if err != nil {
+			return endpointMap, ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return endpointMap, ctrlResult, nil
+		}
+
+		routeSpec := octavia.EndpointRoute(instance, endpointType, selector)
+		if !instance.HasRoute(endpointType) {
+			if err := r.deleteRoute(ctx, routeSpec); err != nil {
+				return endpointMap, ctrl.Result{}, err
+			}
+
+			current := &corev1.Service{}
+			err := r.Client.Get(ctx, types.NamespacedName{Name: svcSpec.Name, Namespace: svcSpec.Namespace}, current)
+			if err != nil {
+				return endpointMap, ctrl.Result{}, err
+			}
+			apiEndpoint := octavia.ServiceEndpointURL(current, octavia.GetEndpointScheme(instance, endpointType))
+			if apiEndpoint == "" {
+				r.Log.Info(fmt.Sprintf("Service %s has no load balancer ingress yet, reconcile in 5s", current.Name))
+				return endpointMap, ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+			}
+			endpointMap[string(endpointType)] = apiEndpoint
+			continue
+		}
+
+		rt := route.NewRoute(routeSpec, routeSpec.Labels, 5)
+		ctrlResult, err = rt.CreateOrPatch(ctx, h)
+		if err != nil {
+			return endpointMap, ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return endpointMap, ctrlResult, nil
+		}
+
+		endpointMap[string(endpointType)] = octavia.RouteEndpointURL(
+			rt.GetHostname(), octavia.GetEndpointScheme(instance, endpointType))
+	}
+
+	return endpointMap, ctrl.Result{}, nil
+}
+
+// deleteRoute - removes a route of an endpoint which is no longer exposed using a route
+func (r *OctaviaAPIReconciler) deleteRoute(ctx context.Context, rt *routev1.Route) error {
+	err := r.Client.Delete(ctx, rt)
+	if err != nil && !k8s_errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("Error deleting route %s: %w", rt.Name, err)
+	}
+	return nil
+}
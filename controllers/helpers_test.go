@@ -0,0 +1,126 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/gomega"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// createNamespace - creates a namespace with a generated name and returns its name
+func createNamespace() string {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "octavia-",
+		},
+	}
+	Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+	return ns.Name
+}
+
+// createOctaviaAPIStub - creates the OctaviaAPI "octavia" with its reconcile paused to keep the
+// status, and sets the conditions, a synced DB and the apiEndpoints in its status
+func createOctaviaAPIStub(namespace string, apiEndpoints map[string]string, conditions ...*condition.Condition) {
+	instance := &octaviav1.OctaviaAPI{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "octavia",
+			Namespace:   namespace,
+			Annotations: map[string]string{octaviav1.ReconcilePausedAnnotation: "true"},
+		},
+		Spec: octaviav1.OctaviaAPISpec{
+			DatabaseInstance: "openstack",
+			ContainerImage:   "quay.io/tripleozedcentos9/openstack-octavia-api:current-tripleo",
+			Secret:           "osp-secret",
+		},
+	}
+	Expect(k8sClient.Create(ctx, instance)).To(Succeed())
+
+	Eventually(func() bool {
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(instance), instance)).To(Succeed())
+		return instance.Status.Conditions.IsTrue(octaviav1.ReconcilePausedCondition)
+	}, timeout, interval).Should(BeTrue())
+	Eventually(func() error {
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(instance), instance)).To(Succeed())
+		instance.Status.DatabaseHostname = "openstack"
+		instance.Status.APIEndpoints = apiEndpoints
+		return k8sClient.Status().Update(ctx, instance)
+	}, timeout, interval).Should(Succeed())
+	setOctaviaAPIConditions(namespace, conditions...)
+}
+
+// setOctaviaAPIConditions - sets the conditions in the status of the OctaviaAPI stub
+func setOctaviaAPIConditions(namespace string, conditions ...*condition.Condition) {
+	instance := &octaviav1.OctaviaAPI{}
+	Eventually(func() error {
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "octavia", Namespace: namespace}, instance)).To(Succeed())
+		for _, c := range conditions {
+			instance.Status.Conditions.Set(c)
+		}
+		return k8sClient.Status().Update(ctx, instance)
+	}, timeout, interval).Should(Succeed())
+}
+
+func isReady(obj interface {
+	client.Object
+	IsReady() bool
+}) func() bool {
+	return func() bool {
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			return false
+		}
+		return obj.IsReady()
+	}
+}
+
+func isDeleted(obj client.Object) func() bool {
+	return func() bool {
+		return k8s_errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj))
+	}
+}
+
+// getCondition - returns the condition t of conditions after refreshing obj, which holds them
+func getCondition(obj client.Object, conditions *condition.Conditions, t condition.Type) func() condition.Condition {
+	return func() condition.Condition {
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			return condition.Condition{}
+		}
+		c := conditions.Get(t)
+		if c == nil {
+			return condition.Condition{}
+		}
+		return *c
+	}
+}
+
+// touch - triggers a reconcile without waiting for the drift requeue
+func touch(obj client.Object) {
+	Eventually(func() error {
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			return err
+		}
+		obj.SetAnnotations(map[string]string{"test": obj.GetResourceVersion()})
+		return k8sClient.Update(ctx, obj)
+	}, timeout, interval).Should(Succeed())
+}
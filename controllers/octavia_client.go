@@ -0,0 +1,132 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	keystonev1 "github.com/openstack-k8s-operators/keystone-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia"
+
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// octaviaClientRequeue - time to wait for the OctaviaAPI to become usable
+const octaviaClientRequeue = time.Second * 10
+
+// getOctaviaClient - returns a client for the internal endpoint of the OctaviaAPI deployed
+// in the namespace. The client authenticates using the keystone admin credentials.
+// The OctaviaAPIReadyCondition of the passed conditions gets updated accordingly.
+func getOctaviaClient(
+	ctx context.Context,
+	h *helper.Helper,
+	namespace string,
+	conditions *condition.Conditions,
+) (*octavia.Client, ctrl.Result, error) {
+	octaviaAPI, err := octaviav1.GetOctaviaAPI(ctx, h, namespace, map[string]string{})
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			conditions.Set(condition.FalseCondition(
+				octaviav1.OctaviaAPIReadyCondition,
+				condition.RequestedReason,
+				condition.SeverityInfo,
+				octaviav1.OctaviaAPIReadyNotFoundMessage))
+			return nil, ctrl.Result{RequeueAfter: octaviaClientRequeue}, nil
+		}
+		conditions.Set(condition.FalseCondition(
+			octaviav1.OctaviaAPIReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			octaviav1.OctaviaAPIReadyErrorMessage,
+			err.Error()))
+		return nil, ctrl.Result{}, err
+	}
+
+	internalURL, err := octaviaAPI.GetEndpoint(endpoint.EndpointInternal)
+	if !octaviaAPI.IsReady() || err != nil {
+		conditions.Set(condition.FalseCondition(
+			octaviav1.OctaviaAPIReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			octaviav1.OctaviaAPIReadyWaitingMessage))
+		return nil, ctrl.Result{RequeueAfter: octaviaClientRequeue}, nil
+	}
+
+	keystoneAPI, err := keystonev1.GetKeystoneAPI(ctx, h, namespace, map[string]string{})
+	if err != nil {
+		conditions.Set(condition.FalseCondition(
+			octaviav1.OctaviaAPIReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			octaviav1.OctaviaAPIReadyErrorMessage,
+			err.Error()))
+		return nil, ctrl.Result{}, err
+	}
+
+	os, ctrlResult, err := keystonev1.GetAdminServiceClient(ctx, h, keystoneAPI)
+	if err != nil {
+		conditions.Set(condition.FalseCondition(
+			octaviav1.OctaviaAPIReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			octaviav1.OctaviaAPIReadyErrorMessage,
+			err.Error()))
+		return nil, ctrl.Result{}, err
+	}
+	if (ctrlResult != ctrl.Result{}) {
+		conditions.Set(condition.FalseCondition(
+			octaviav1.OctaviaAPIReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			octaviav1.OctaviaAPIReadyWaitingMessage))
+		return nil, ctrlResult, nil
+	}
+
+	conditions.MarkTrue(octaviav1.OctaviaAPIReadyCondition, octaviav1.OctaviaAPIReadyMessage)
+
+	return octavia.NewClient(os.GetOSClient().ProviderClient, internalURL), ctrl.Result{}, nil
+}
+
+// getOctaviaClientForDelete - returns a client like getOctaviaClient, but no client if the
+// OctaviaAPI is not found or gets deleted. The resources can not be removed from Octavia then,
+// which must not block deleting them e.g. on namespace teardown. An OctaviaAPI which is not ready,
+// e.g. in maintenance mode or during a DB restore, requeues like getOctaviaClient.
+func getOctaviaClientForDelete(
+	ctx context.Context,
+	h *helper.Helper,
+	namespace string,
+	conditions *condition.Conditions,
+) (*octavia.Client, ctrl.Result, error) {
+	octaviaAPI, err := octaviav1.GetOctaviaAPI(ctx, h, namespace, map[string]string{})
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return nil, ctrl.Result{}, nil
+		}
+		return nil, ctrl.Result{}, err
+	}
+	if !octaviaAPI.DeletionTimestamp.IsZero() {
+		return nil, ctrl.Result{}, nil
+	}
+
+	return getOctaviaClient(ctx, h, namespace, conditions)
+}
@@ -29,7 +29,6 @@ import (
 	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/configmap"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/deployment"
-	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/job"
@@ -39,25 +38,39 @@ import (
 	"github.com/openstack-k8s-operators/lib-common/modules/database"
 	mariadbv1 "github.com/openstack-k8s-operators/mariadb-operator/api/v1beta1"
 	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/metrics"
 	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // OctaviaAPIReconciler reconciles a OctaviaAPI object
 type OctaviaAPIReconciler struct {
 	client.Client
-	Kclient kubernetes.Interface
-	Log     logr.Logger
-	Scheme  *runtime.Scheme
+	Kclient  kubernetes.Interface
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=octavia.openstack.org,resources=octaviaapis,verbs=get;list;watch;create;update;patch;delete
@@ -66,13 +79,21 @@ type OctaviaAPIReconciler struct {
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete;
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete;
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete;
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch;
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
+// +kubebuilder:rbac:groups=core,resources=pods/log,verbs=get;
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete;
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete;
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete;
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete;
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete;
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch;delete;
 // +kubebuilder:rbac:groups=mariadb.openstack.org,resources=mariadbdatabases,verbs=get;list;watch;create;update;patch;delete;
 // +kubebuilder:rbac:groups=keystone.openstack.org,resources=keystoneapis,verbs=get;list;watch;
 // +kubebuilder:rbac:groups=keystone.openstack.org,resources=keystoneservices,verbs=get;list;watch;create;update;patch;delete;
 // +kubebuilder:rbac:groups=keystone.openstack.org,resources=keystoneendpoints,verbs=get;list;watch;create;update;patch;delete;
+// +kubebuilder:rbac:groups=ovn.openstack.org,resources=ovndbclusters,verbs=get;list;watch;
+// +kubebuilder:rbac:groups=memcached.openstack.org,resources=memcacheds,verbs=get;list;watch;
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -143,9 +164,22 @@ func (r *OctaviaAPIReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	// Always patch the instance status when exiting this function so we can persist any changes.
 	defer func() {
-		// update the overall status condition if service is ready
+		// update the overall status condition, which reports the first phase not ready yet
 		if instance.IsReady() {
 			instance.Status.Conditions.MarkTrue(condition.ReadyCondition, condition.ReadyMessage)
+		} else {
+			instance.Status.Conditions.Set(notReadyCondition(instance.Status.Conditions))
+		}
+
+		// emit events for the reconcile phases which changed their state
+		if before, ok := helper.GetBeforeObject().(*octaviav1.OctaviaAPI); ok {
+			recordConditionEvents(r.Recorder, instance, before.Status.Conditions, instance.Status.Conditions)
+			if instance.IsReady() && !before.Status.Conditions.IsTrue(condition.ReadyCondition) {
+				metrics.ObserveTimeToReady(instance)
+			}
+		}
+		if instance.DeletionTimestamp.IsZero() {
+			metrics.SetInstanceStatus(instance)
 		}
 
 		if err := helper.SetAfter(instance); err != nil {
@@ -161,6 +195,12 @@ func (r *OctaviaAPIReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 	}()
 
+	// Only report the status while the reconcile is paused
+	if instance.IsReconcilePaused() {
+		return r.reconcilePaused(ctx, instance, helper)
+	}
+	removeCondition(&instance.Status.Conditions, octaviav1.ReconcilePausedCondition)
+
 	// Handle service delete
 	if !instance.DeletionTimestamp.IsZero() {
 		return r.reconcileDelete(ctx, instance, helper)
@@ -172,22 +212,113 @@ func (r *OctaviaAPIReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *OctaviaAPIReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	// reconcile the OctaviaAPIs using a Memcached instance when it changes
+	memcachedFn := func(o client.Object) []reconcile.Request {
+		return r.getOctaviaAPIRequests(o, func(instance *octaviav1.OctaviaAPI) bool {
+			return instance.Spec.MemcachedInstance == o.GetName()
+		})
+	}
+
+	// reconcile the OctaviaAPIs with the ovn provider enabled when an OVN DB cluster changes
+	ovnDBClusterFn := func(o client.Object) []reconcile.Request {
+		return r.getOctaviaAPIRequests(o, func(instance *octaviav1.OctaviaAPI) bool {
+			return instance.IsProviderEnabled(octaviav1.ProviderDriverOVN)
+		})
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&octaviav1.OctaviaAPI{}).
 		Owns(&mariadbv1.MariaDBDatabase{}).
 		Owns(&keystonev1.KeystoneService{}).
 		Owns(&keystonev1.KeystoneEndpoint{}).
 		Owns(&batchv1.Job{}).
+		Owns(&batchv1.CronJob{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.Secret{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&appsv1.Deployment{}).
-		Owns(&routev1.Route{}).
-		Complete(r)
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
+		Owns(&routev1.Route{})
+
+	// the Memcached and OVNDBCluster CRDs are optional and only get watched if they are
+	// installed when the operator starts
+	for _, w := range []struct {
+		gvk schema.GroupVersionKind
+		fn  handler.MapFunc
+	}{
+		{octavia.MemcachedGVK, memcachedFn},
+		{octavia.OVNDBClusterGVK, ovnDBClusterFn},
+	} {
+		if _, err := mgr.GetRESTMapper().RESTMapping(w.gvk.GroupKind(), w.gvk.Version); err != nil {
+			if meta.IsNoMatchError(err) {
+				r.Log.Info(fmt.Sprintf("%s CRD not installed, not watching it", w.gvk.Kind))
+				continue
+			}
+			return err
+		}
+		watched := &unstructured.Unstructured{}
+		watched.SetGroupVersionKind(w.gvk)
+		b = b.Watches(&source.Kind{Type: watched}, handler.EnqueueRequestsFromMapFunc(w.fn))
+	}
+
+	return b.Complete(r)
+}
+
+//
+// getOctaviaAPIRequests - returns the reconcile requests of the OctaviaAPIs in the namespace of o
+// which match
+//
+func (r *OctaviaAPIReconciler) getOctaviaAPIRequests(o client.Object, match func(*octaviav1.OctaviaAPI) bool) []reconcile.Request {
+	result := []reconcile.Request{}
+
+	instances := &octaviav1.OctaviaAPIList{}
+	if err := r.Client.List(context.Background(), instances, client.InNamespace(o.GetNamespace())); err != nil {
+		r.Log.Error(err, "Unable to retrieve OctaviaAPI CRs")
+		return nil
+	}
+
+	for i := range instances.Items {
+		instance := &instances.Items[i]
+		if match(instance) {
+			r.Log.Info(fmt.Sprintf("%s %s is used by OctaviaAPI CR %s", o.GetObjectKind().GroupVersionKind().Kind, o.GetName(), instance.Name))
+			result = append(result, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(instance)})
+		}
+	}
+
+	return result
+}
+
+func (r *OctaviaAPIReconciler) reconcilePaused(ctx context.Context, instance *octaviav1.OctaviaAPI, helper *helper.Helper) (ctrl.Result, error) {
+	util.LogForObject(helper, "Reconcile paused, only reporting the status", instance)
+
+	instance.Status.Conditions.Set(condition.TrueCondition(
+		octaviav1.ReconcilePausedCondition,
+		octaviav1.ReconcilePausedMessage,
+		octaviav1.ReconcilePausedAnnotation))
+
+	// refresh the ready count from the deployment, if it exists
+	depl := &appsv1.Deployment{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: octavia.ServiceName, Namespace: instance.Namespace}, depl)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	instance.Status.ReadyCount = depl.Status.ReadyReplicas
+
+	return ctrl.Result{}, nil
 }
 
 func (r *OctaviaAPIReconciler) reconcileDelete(ctx context.Context, instance *octaviav1.OctaviaAPI, helper *helper.Helper) (ctrl.Result, error) {
 	util.LogForObject(helper, "Reconciling Service delete", instance)
+	defer metrics.ObserveReconcilePhase(metrics.PhaseDelete, time.Now())
+
+	// revoke the application credentials while the service user still exists
+	if err := r.revokeApplicationCredential(ctx, instance, helper); err != nil {
+		return ctrl.Result{}, err
+	}
 
 	// Remove the finalizer from our KeystoneEndpoint CR
 	keystoneEndpoint, err := keystonev1.GetKeystoneEndpointWithName(ctx, helper, octavia.ServiceName, instance.Namespace)
@@ -224,6 +355,8 @@ func (r *OctaviaAPIReconciler) reconcileDelete(ctx context.Context, instance *oc
 		return ctrl.Result{}, err
 	}
 
+	metrics.DeleteInstance(instance.Namespace, instance.Name)
+
 	util.LogForObject(helper, "Reconciled Service delete successfully", instance)
 	return ctrl.Result{}, nil
 }
@@ -235,6 +368,7 @@ func (r *OctaviaAPIReconciler) reconcileInit(
 	serviceLabels map[string]string,
 ) (ctrl.Result, error) {
 	r.Log.Info("Reconciling Service init")
+	defer metrics.ObserveReconcilePhase(metrics.PhaseInit, time.Now())
 
 	//
 	// create service DB instance
@@ -300,6 +434,19 @@ func (r *OctaviaAPIReconciler) reconcileInit(
 	//
 	dbSyncHash := instance.Status.Hash[octaviav1.DbSyncHash]
 	jobDef := octavia.DbSyncJob(instance, serviceLabels)
+
+	// the job gets run if its hash changed, dump the DB first if it upgrades it to a new image
+	dbSyncJobHash, err := util.ObjectHash(jobDef)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	ctrlResult, err = r.reconcilePreUpgradeBackup(ctx, instance, helper, serviceLabels, dbSyncJobHash)
+	if err != nil {
+		return ctrlResult, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+
 	dbSyncjob := job.NewJob(
 		jobDef,
 		octaviav1.DbSyncHash,
@@ -307,6 +454,12 @@ func (r *OctaviaAPIReconciler) reconcileInit(
 		5,
 		dbSyncHash,
 	)
+	// the job gets deleted by DoJob once it succeeded, get it before to record its duration.
+	// If it does not exist (yet), an empty job gets returned which is ignored by the metrics.
+	finishedJob, err := job.GetJobWithName(ctx, helper, jobDef.Name, jobDef.Namespace)
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
 	ctrlResult, err = dbSyncjob.DoJob(
 		ctx,
 		helper,
@@ -320,6 +473,33 @@ func (r *OctaviaAPIReconciler) reconcileInit(
 		return ctrlResult, nil
 	}
 	if err != nil {
+		// failed pods get retried until the backoff limit or the deadline of the job is exceeded
+		if failedJob, getErr := job.GetJobWithName(ctx, helper, jobDef.Name, jobDef.Namespace); getErr == nil {
+			finished, failure := getJobResult(failedJob)
+			if !finished {
+				instance.Status.Conditions.Set(condition.FalseCondition(
+					condition.DBSyncReadyCondition,
+					condition.RequestedReason,
+					condition.SeverityInfo,
+					condition.DBSyncReadyRunningMessage))
+				return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+			}
+			if failure != "" {
+				logs, logErr := getJobLogTail(ctx, helper.GetKClient(), failedJob)
+				if logErr != nil {
+					r.Log.Info(fmt.Sprintf("Failed to get the logs of job %s: %s", failedJob.Name, logErr))
+				} else if logs != "" {
+					failure = fmt.Sprintf("%s, %s", failure, logs)
+				}
+				err = fmt.Errorf("job %s failed, %s", failedJob.Name, failure)
+			}
+		}
+
+		// record a failed job once, not on every reconcile waiting for it to get fixed
+		dbSyncCondition := instance.Status.Conditions.Get(condition.DBSyncReadyCondition)
+		if dbSyncCondition == nil || dbSyncCondition.Reason != condition.ErrorReason {
+			metrics.ObserveDBSyncJob(finishedJob)
+		}
 		instance.Status.Conditions.Set(condition.FalseCondition(
 			condition.DBSyncReadyCondition,
 			condition.ErrorReason,
@@ -329,11 +509,14 @@ func (r *OctaviaAPIReconciler) reconcileInit(
 		return ctrl.Result{}, err
 	}
 	if dbSyncjob.HasChanged() {
+		metrics.ObserveDBSyncJob(finishedJob)
 		instance.Status.Hash[octaviav1.DbSyncHash] = dbSyncjob.GetHash()
 		if err := r.Client.Status().Update(ctx, instance); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
+	// the image is part of the job, it did not change if the job did not run
+	instance.Status.DBSyncImage = instance.Spec.ContainerImage
 	instance.Status.Conditions.MarkTrue(condition.DBSyncReadyCondition, condition.DBSyncReadyMessage)
 
 	// run octavia db sync - end
@@ -345,25 +528,7 @@ func (r *OctaviaAPIReconciler) reconcileInit(
 	//
 	// expose the service (create service, route and return the created endpoint URLs)
 	//
-	var octaviaPorts = map[endpoint.Endpoint]endpoint.Data{
-		endpoint.EndpointAdmin: endpoint.Data{
-			Port: octavia.OctaviaAdminPort,
-		},
-		endpoint.EndpointPublic: endpoint.Data{
-			Port: octavia.OctaviaPublicPort,
-		},
-		endpoint.EndpointInternal: endpoint.Data{
-			Port: octavia.OctaviaInternalPort,
-		},
-	}
-
-	apiEndpoints, ctrlResult, err := endpoint.ExposeEndpoints(
-		ctx,
-		helper,
-		octavia.ServiceName,
-		serviceLabels,
-		octaviaPorts,
-	)
+	apiEndpoints, ctrlResult, err := r.exposeEndpoints(ctx, instance, helper, serviceLabels)
 	if err != nil {
 		instance.Status.Conditions.Set(condition.FalseCondition(
 			condition.ExposeServiceReadyCondition,
@@ -383,9 +548,8 @@ func (r *OctaviaAPIReconciler) reconcileInit(
 	instance.Status.Conditions.MarkTrue(condition.ExposeServiceReadyCondition, condition.ExposeServiceReadyMessage)
 
 	//
-	// Update instance status with service endpoint url from route host information
+	// Update instance status with the endpoint URLs, from the route host or the service
 	//
-	// TODO: need to support https default here
 	if instance.Status.APIEndpoints == nil {
 		instance.Status.APIEndpoints = map[string]string{}
 	}
@@ -461,6 +625,7 @@ func (r *OctaviaAPIReconciler) registerInKeystone(
 
 func (r *OctaviaAPIReconciler) reconcileUpdate(ctx context.Context, instance *octaviav1.OctaviaAPI, helper *helper.Helper) (ctrl.Result, error) {
 	r.Log.Info("Reconciling Service update")
+	defer metrics.ObserveReconcilePhase(metrics.PhaseUpdate, time.Now())
 
 	// TODO: should have minor update tasks if required
 	// - delete dbsync hash from status to rerun it?
@@ -471,6 +636,7 @@ func (r *OctaviaAPIReconciler) reconcileUpdate(ctx context.Context, instance *oc
 
 func (r *OctaviaAPIReconciler) reconcileUpgrade(ctx context.Context, instance *octaviav1.OctaviaAPI, helper *helper.Helper) (ctrl.Result, error) {
 	r.Log.Info("Reconciling Service upgrade")
+	defer metrics.ObserveReconcilePhase(metrics.PhaseUpgrade, time.Now())
 
 	// TODO: should have major version upgrade tasks
 	// -delete dbsync hash from status to rerun it?
@@ -482,6 +648,7 @@ func (r *OctaviaAPIReconciler) reconcileUpgrade(ctx context.Context, instance *o
 // TODO(tweining): implement
 func (r *OctaviaAPIReconciler) reconcileNormal(ctx context.Context, instance *octaviav1.OctaviaAPI, helper *helper.Helper) (ctrl.Result, error) {
 	r.Log.Info("Reconciling Service")
+	defer metrics.ObserveReconcilePhase(metrics.PhaseNormal, time.Now())
 
 	if !controllerutil.ContainsFinalizer(instance, helper.GetFinalizer()) {
 		// If the service object doesn't have our finalizer, add it.
@@ -519,10 +686,81 @@ func (r *OctaviaAPIReconciler) reconcileNormal(ctx context.Context, instance *oc
 	}
 	configMapVars[ospSecret.Name] = env.SetValue(hash)
 
-	instance.Status.Conditions.MarkTrue(condition.InputReadyCondition, condition.InputReadyMessage)
-
 	// run check OpenStack secret - end
 
+	templateParameters := make(map[string]interface{})
+
+	//
+	// discover the OVN NB and SB DB endpoints required by the ovn provider driver
+	//
+	if instance.IsProviderEnabled(octaviav1.ProviderDriverOVN) {
+		ctrlResult, err := r.getOVNDBConnections(ctx, instance, helper, templateParameters)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+	} else {
+		removeCondition(&instance.Status.Conditions, octaviav1.OVNDBReadyCondition)
+	}
+
+	// discover OVN DBs - end
+
+	//
+	// discover the servers of the Memcached instance used for the token cache
+	//
+	if instance.Spec.MemcachedInstance != "" {
+		ctrlResult, err := r.getMemcachedServers(ctx, instance, helper, templateParameters)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+	} else {
+		removeCondition(&instance.Status.Conditions, octaviav1.MemcachedReadyCondition)
+	}
+
+	// discover Memcached - end
+
+	//
+	// get the public keystone endpoint used as auth_url of the service
+	//
+	keystoneAPI, err := keystonev1.GetKeystoneAPI(ctx, helper, instance.Namespace, map[string]string{})
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				condition.InputReadyCondition,
+				condition.RequestedReason,
+				condition.SeverityInfo,
+				condition.InputReadyWaitingMessage))
+			return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+		}
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.InputReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.InputReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+	keystoneParameters, err := octavia.GetKeystoneParameters(keystoneAPI)
+	if err != nil {
+		// the KeystoneAPI did not yet expose its endpoints
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.InputReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			condition.InputReadyWaitingMessage))
+		return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+	}
+	for key, value := range keystoneParameters {
+		templateParameters[key] = value
+	}
+
+	// get keystone endpoint - end
+
+	instance.Status.Conditions.MarkTrue(condition.InputReadyCondition, condition.InputReadyMessage)
+
 	//
 	// Create ConfigMaps and Secrets required as input for the Service and calculate an overall hash of hashes
 	//
@@ -533,7 +771,7 @@ func (r *OctaviaAPIReconciler) reconcileNormal(ctx context.Context, instance *oc
 	// - %-config configmap holding minimal octavia config required to get the service up, user can add additional files to be added to the service
 	// - parameters which has passwords gets added from the OpenStack secret via the init container
 	//
-	err = r.generateServiceConfigMaps(ctx, instance, helper, &configMapVars)
+	err = r.generateServiceConfigMaps(ctx, instance, helper, &configMapVars, templateParameters)
 	if err != nil {
 		instance.Status.Conditions.Set(condition.FalseCondition(
 			condition.ServiceConfigReadyCondition,
@@ -544,15 +782,6 @@ func (r *OctaviaAPIReconciler) reconcileNormal(ctx context.Context, instance *oc
 		return ctrl.Result{}, err
 	}
 
-	//
-	// create hash over all the different input resources to identify if any those changed
-	// and a restart/recreate is required.
-	//
-	inputHash, err := r.createHashOfInputHashes(ctx, instance, configMapVars)
-	if err != nil {
-		return ctrl.Result{}, err
-	}
-
 	instance.Status.Conditions.MarkTrue(condition.ServiceConfigReadyCondition, condition.ServiceConfigReadyMessage)
 
 	// Create ConfigMaps and Secrets - end
@@ -589,17 +818,103 @@ func (r *OctaviaAPIReconciler) reconcileNormal(ctx context.Context, instance *oc
 		return ctrlResult, nil
 	}
 
+	//
+	// the application credential requires the service user registered by reconcileInit. Its hash
+	// gets added to the input hashes to restart the pods on a rotation.
+	//
+	appCredHash, appCredResult, err := r.reconcileApplicationCredential(
+		ctx,
+		instance,
+		helper,
+		serviceLabels,
+		keystoneParameters["KeystonePublicURL"].(string),
+		string(ospSecret.Data[instance.Spec.PasswordSelectors.Service]))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if appCredHash != "" {
+		configMapVars[octavia.ApplicationCredentialSecretName(instance)] = env.SetValue(appCredHash)
+	}
+
+	//
+	// create hash over all the different input resources to identify if any those changed
+	// and a restart/recreate is required.
+	//
+	inputHash, err := r.createHashOfInputHashes(ctx, instance, configMapVars)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	//
 	// normal reconcile tasks
 	//
 
+	ctrlResult, err = r.reconcileDeployment(ctx, instance, helper, inputHash, serviceLabels)
+	if err != nil {
+		return ctrlResult, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+
+	err = r.reconcileDBPurge(ctx, instance, helper, serviceLabels)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	//
+	// deploy the optional stats exporter
+	//
+	ctrlResult, err = r.reconcileExporter(ctx, instance, helper, keystoneParameters["KeystonePublicURL"].(string))
+	if err != nil {
+		return ctrlResult, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+
+	// deploy exporter - end
+
+	// the status reflects the reconcile of the current spec
+	instance.Status.ObservedGeneration = instance.Generation
+
+	r.Log.Info("Reconciled Service successfully")
+	// requeue for the next rotation step of the application credential
+	return appCredResult, nil
+}
+
+//
+// reconcileDeployment - create or patch the API deployment with its PodDisruptionBudget and
+// HorizontalPodAutoscaler and mirror the rollout into the DeploymentReady condition
+//
+func (r *OctaviaAPIReconciler) reconcileDeployment(
+	ctx context.Context,
+	instance *octaviav1.OctaviaAPI,
+	helper *helper.Helper,
+	inputHash string,
+	serviceLabels map[string]string,
+) (ctrl.Result, error) {
+	defer metrics.ObserveReconcilePhase(metrics.PhaseDeployment, time.Now())
+
 	// Define a new Deployment object
+	deploymentDef := octavia.Deployment(instance, inputHash, serviceLabels)
+
+	// while autoscaling, the replicas of the deployment are managed by the HPA and must not get reset
+	if instance.IsAutoscalingEnabled() {
+		current := &appsv1.Deployment{}
+		err := r.Client.Get(ctx, types.NamespacedName{Name: deploymentDef.Name, Namespace: deploymentDef.Namespace}, current)
+		if err != nil && !k8s_errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		if err == nil && current.Spec.Replicas != nil {
+			deploymentDef.Spec.Replicas = current.Spec.Replicas
+		}
+	}
+
 	depl := deployment.NewDeployment(
-		octavia.Deployment(instance, inputHash, serviceLabels),
+		deploymentDef,
 		5,
 	)
 
-	ctrlResult, err = depl.CreateOrPatch(ctx, helper)
+	ctrlResult, err := depl.CreateOrPatch(ctx, helper)
 	if err != nil {
 		instance.Status.Conditions.Set(condition.FalseCondition(
 			condition.DeploymentReadyCondition,
@@ -616,13 +931,66 @@ func (r *OctaviaAPIReconciler) reconcileNormal(ctx context.Context, instance *oc
 			condition.DeploymentReadyRunningMessage))
 		return ctrlResult, nil
 	}
-	instance.Status.ReadyCount = depl.GetDeployment().Status.ReadyReplicas
-	if instance.Status.ReadyCount > 0 {
+	deploymentObj := depl.GetDeployment()
+
+	// the deployment module only patches the replicas and the pod template
+	err = r.ensureDeploymentStrategy(ctx, &deploymentObj, octavia.DeploymentStrategy(instance))
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.DeploymentReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.DeploymentReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+
+	err = r.reconcilePodDisruptionBudget(ctx, instance, helper, serviceLabels)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.DeploymentReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.DeploymentReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+
+	hpa, err := r.reconcileAutoscaler(ctx, instance, helper, serviceLabels)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.DeploymentReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.DeploymentReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+
+	instance.Status.ReadyCount = deploymentObj.Status.ReadyReplicas
+	instance.Status.Replicas = deploymentObj.Status.Replicas
+	instance.Status.DesiredReplicas = instance.GetReplicas()
+	if hpa != nil && hpa.Status.DesiredReplicas > 0 {
+		instance.Status.DesiredReplicas = hpa.Status.DesiredReplicas
+	}
+	if instance.IsMaintenance() {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.DeploymentReadyCondition,
+			octaviav1.MaintenanceReason,
+			condition.SeverityInfo,
+			octaviav1.DeploymentReadyMaintenanceMessage))
+	} else if octavia.IsDeploymentReady(&deploymentObj) {
 		instance.Status.Conditions.MarkTrue(condition.DeploymentReadyCondition, condition.DeploymentReadyMessage)
+	} else {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.DeploymentReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			octaviav1.DeploymentReadyRolloutMessage,
+			deploymentObj.Status.AvailableReplicas,
+			instance.Status.DesiredReplicas))
 	}
-	// create Deployment - end
 
-	r.Log.Info("Reconciled Service successfully")
 	return ctrl.Result{}, nil
 }
 
@@ -635,6 +1003,7 @@ func (r *OctaviaAPIReconciler) generateServiceConfigMaps(
 	instance *octaviav1.OctaviaAPI,
 	h *helper.Helper,
 	envVars *map[string]env.Setter,
+	templateParameters map[string]interface{},
 ) error {
 	//
 	// create Configmap/Secret required for octavia input
@@ -645,46 +1014,276 @@ func (r *OctaviaAPIReconciler) generateServiceConfigMaps(
 
 	cmLabels := labels.GetLabels(instance, labels.GetGroupLabel(octavia.ServiceName), map[string]string{})
 
-	// customData hold any customization for the service.
-	// custom.conf is going to /etc/<service>/<service>.conf.d
-	// all other files get placed into /etc/<service> to allow overwrite of e.g. logging.conf or policy.json
-	// TODO: make sure custom.conf can not be overwritten
-	customData := map[string]string{common.CustomServiceConfigFileName: instance.Spec.CustomServiceConfig}
-	for key, data := range instance.Spec.DefaultConfigOverwrite {
-		customData[key] = data
+	// endpoints sharing a virtual host can not have different TLS settings
+	if err := octavia.ValidateEndpoints(instance); err != nil {
+		return err
 	}
 
-	templateParameters := make(map[string]interface{})
+	// bind to the wildcard address of the IP families the endpoint services ask for
+	clusterFamilies, err := octavia.GetClusterIPFamilies(ctx, h)
+	if err != nil {
+		return err
+	}
+	templateParameters["BindHost"] = octavia.GetBindHost(octavia.GetEndpointIPFamilies(instance, clusterFamilies))
+
+	// settings derived from the spec, discovered values like the OVN DB connections are
+	// already part of the passed templateParameters
+	for key, value := range octavia.GetServiceConfigParameters(instance) {
+		templateParameters[key] = value
+	}
+
+	cms := octavia.ServiceConfigTemplates(instance, cmLabels, templateParameters)
+	err = configmap.EnsureConfigMaps(ctx, h, instance, cms, envVars)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+//
+// getOVNDBConnections - discover the connections of the OVN NB and SB DB clusters and add them to the template parameters
+//
+func (r *OctaviaAPIReconciler) getOVNDBConnections(
+	ctx context.Context,
+	instance *octaviav1.OctaviaAPI,
+	h *helper.Helper,
+	templateParameters map[string]interface{},
+) (ctrl.Result, error) {
+	for param, dbType := range map[string]string{
+		"NBConnection": octavia.OVNDBTypeNB,
+		"SBConnection": octavia.OVNDBTypeSB,
+	} {
+		connection, err := octavia.GetOVNDBConnection(ctx, h, instance.Namespace, dbType)
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				octaviav1.OVNDBReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				octaviav1.OVNDBReadyErrorMessage,
+				err.Error()))
+			return ctrl.Result{}, err
+		}
+		if connection == "" {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				octaviav1.OVNDBReadyCondition,
+				condition.RequestedReason,
+				condition.SeverityInfo,
+				octaviav1.OVNDBReadyWaitingMessage,
+				dbType))
+			return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+		}
+		templateParameters[param] = connection
+	}
+	instance.Status.Conditions.MarkTrue(octaviav1.OVNDBReadyCondition, octaviav1.OVNDBReadyMessage)
+
+	return ctrl.Result{}, nil
+}
+
+//
+// getMemcachedServers - discover the servers of the Memcached instance and add them to the template parameters
+//
+func (r *OctaviaAPIReconciler) getMemcachedServers(
+	ctx context.Context,
+	instance *octaviav1.OctaviaAPI,
+	h *helper.Helper,
+	templateParameters map[string]interface{},
+) (ctrl.Result, error) {
+	servers, err := octavia.GetMemcachedServers(ctx, h, instance.Namespace, instance.Spec.MemcachedInstance)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.MemcachedReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			octaviav1.MemcachedReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+	if len(servers) == 0 {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.MemcachedReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			octaviav1.MemcachedReadyWaitingMessage,
+			instance.Spec.MemcachedInstance))
+		return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+	}
+	templateParameters["MemcachedServers"] = octavia.MemcachedServers(servers)
+	instance.Status.Conditions.MarkTrue(octaviav1.MemcachedReadyCondition, octaviav1.MemcachedReadyMessage)
+
+	return ctrl.Result{}, nil
+}
 
-	cms := []util.Template{
-		// ScriptsConfigMap
-		{
-			Name:               fmt.Sprintf("%s-scripts", instance.Name),
-			Namespace:          instance.Namespace,
-			Type:               util.TemplateTypeScripts,
-			InstanceType:       instance.Kind,
-			AdditionalTemplate: map[string]string{"common.sh": "/common/common.sh"},
-			Labels:             cmLabels,
+//
+// ensureDeploymentStrategy - patches the rolling update strategy of the deployment if it differs
+//
+func (r *OctaviaAPIReconciler) ensureDeploymentStrategy(
+	ctx context.Context,
+	depl *appsv1.Deployment,
+	strategy appsv1.DeploymentStrategy,
+) error {
+	if equality.Semantic.DeepEqual(depl.Spec.Strategy, strategy) {
+		return nil
+	}
+
+	patch := client.MergeFrom(depl.DeepCopy())
+	depl.Spec.Strategy = strategy
+	return r.Client.Patch(ctx, depl, patch)
+}
+
+//
+// reconcilePodDisruptionBudget - creates or patches the PDB of the API pods, or deletes it if
+// less than two replicas are running
+//
+func (r *OctaviaAPIReconciler) reconcilePodDisruptionBudget(
+	ctx context.Context,
+	instance *octaviav1.OctaviaAPI,
+	h *helper.Helper,
+	serviceLabels map[string]string,
+) error {
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      octavia.ServiceName,
+			Namespace: instance.Namespace,
 		},
-		// ConfigMap
-		{
-			Name:          fmt.Sprintf("%s-config-data", instance.Name),
-			Namespace:     instance.Namespace,
-			Type:          util.TemplateTypeConfig,
-			InstanceType:  instance.Kind,
-			CustomData:    customData,
-			ConfigOptions: templateParameters,
-			Labels:        cmLabels,
+	}
+
+	desired := octavia.PodDisruptionBudget(instance, serviceLabels)
+	if desired == nil {
+		err := r.Client.Delete(ctx, pdb)
+		if err != nil && !k8s_errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	op, err := controllerutil.CreateOrPatch(ctx, r.Client, pdb, func() error {
+		pdb.Labels = util.MergeStringMaps(pdb.Labels, desired.Labels)
+		pdb.Spec = desired.Spec
+
+		return controllerutil.SetControllerReference(instance, pdb, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		util.LogForObject(h, fmt.Sprintf("PodDisruptionBudget %s - %s", pdb.Name, op), instance)
+	}
+
+	return nil
+}
+
+//
+// reconcileDBPurge - creates or patches the cronjob purging the deleted load balancers and amphorae from the DB
+//
+func (r *OctaviaAPIReconciler) reconcileDBPurge(
+	ctx context.Context,
+	instance *octaviav1.OctaviaAPI,
+	h *helper.Helper,
+	serviceLabels map[string]string,
+) error {
+	desired := octavia.DbPurgeCronJob(instance, serviceLabels)
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      desired.Name,
+			Namespace: desired.Namespace,
 		},
 	}
-	err := configmap.EnsureConfigMaps(ctx, h, instance, cms, envVars)
+
+	op, err := controllerutil.CreateOrPatch(ctx, r.Client, cronJob, func() error {
+		cronJob.Labels = util.MergeStringMaps(cronJob.Labels, desired.Labels)
+		cronJob.Spec = desired.Spec
+
+		return controllerutil.SetControllerReference(instance, cronJob, r.Scheme)
+	})
 	if err != nil {
-		return nil
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		util.LogForObject(h, fmt.Sprintf("CronJob %s - %s", cronJob.Name, op), instance)
 	}
 
 	return nil
 }
 
+//
+// reconcileAutoscaler - creates or patches the HPA of the API deployment if autoscaling is enabled,
+// otherwise deletes it. Returns the current HPA or nil.
+//
+func (r *OctaviaAPIReconciler) reconcileAutoscaler(
+	ctx context.Context,
+	instance *octaviav1.OctaviaAPI,
+	h *helper.Helper,
+	serviceLabels map[string]string,
+) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      octavia.ServiceName,
+			Namespace: instance.Namespace,
+		},
+	}
+
+	desired := octavia.HorizontalPodAutoscaler(instance, serviceLabels)
+	if desired == nil {
+		err := r.Client.Delete(ctx, hpa)
+		if err != nil && !k8s_errors.IsNotFound(err) {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	op, err := controllerutil.CreateOrPatch(ctx, r.Client, hpa, func() error {
+		hpa.Labels = util.MergeStringMaps(hpa.Labels, desired.Labels)
+		hpa.Spec = desired.Spec
+
+		return controllerutil.SetControllerReference(instance, hpa, r.Scheme)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if op != controllerutil.OperationResultNone {
+		util.LogForObject(h, fmt.Sprintf("HorizontalPodAutoscaler %s - %s", hpa.Name, op), instance)
+	}
+
+	return hpa, nil
+}
+
+//
+// removeCondition - removes the condition of type t from the list, if it exists
+//
+func removeCondition(conditions *condition.Conditions, t condition.Type) {
+	for i, c := range *conditions {
+		if c.Type == t {
+			*conditions = append((*conditions)[:i], (*conditions)[i+1:]...)
+			return
+		}
+	}
+}
+
+//
+// notReadyCondition - returns a False ReadyCondition with the reason and message of the first
+// False condition, or else of the first condition which is not true yet
+//
+func notReadyCondition(conditions condition.Conditions) *condition.Condition {
+	var notTrue *condition.Condition
+	for i := range conditions {
+		c := &conditions[i]
+		if c.Type == condition.ReadyCondition || c.Status == corev1.ConditionTrue {
+			continue
+		}
+		if c.Status == corev1.ConditionFalse {
+			return condition.FalseCondition(condition.ReadyCondition, c.Reason, c.Severity, "%s", c.Message)
+		}
+		if notTrue == nil {
+			notTrue = c
+		}
+	}
+	if notTrue != nil {
+		return condition.FalseCondition(condition.ReadyCondition, notTrue.Reason, condition.SeverityInfo, "%s", notTrue.Message)
+	}
+	return condition.FalseCondition(condition.ReadyCondition, condition.RequestedReason, condition.SeverityInfo, condition.ReadyInitMessage)
+}
+
 //
 // createHashOfInputHashes - creates a hash of hashes which gets added to the resources which requires a restart
 // if any of the input resources change, like configs, passwords, ...
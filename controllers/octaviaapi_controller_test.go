@@ -0,0 +1,656 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	keystonev1 "github.com/openstack-k8s-operators/keystone-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
+	mariadbv1 "github.com/openstack-k8s-operators/mariadb-operator/api/v1beta1"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia/octaviatest"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// the reconciler requeues with up to 10s while waiting for dependencies
+	timeout  = time.Second * 30
+	interval = time.Millisecond * 250
+)
+
+var _ = Describe("OctaviaAPI controller", func() {
+	var namespace string
+	var octaviaName types.NamespacedName
+	// name of the deployment and the objects managed along with it
+	var apiName types.NamespacedName
+
+	getOctaviaAPI := func() *octaviav1.OctaviaAPI {
+		instance := &octaviav1.OctaviaAPI{}
+		Expect(k8sClient.Get(ctx, octaviaName, instance)).To(Succeed())
+		return instance
+	}
+
+	conditionStatus := func(t condition.Type) func() corev1.ConditionStatus {
+		return func() corev1.ConditionStatus {
+			c := getOctaviaAPI().Status.Conditions.Get(t)
+			if c == nil {
+				return corev1.ConditionUnknown
+			}
+			return c.Status
+		}
+	}
+
+	createSecret := func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "osp-secret",
+				Namespace: namespace,
+			},
+			StringData: map[string]string{
+				"OctaviaDatabasePassword": "12345678",
+				"OctaviaPassword":         "12345678",
+			},
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+	}
+
+	createKeystoneAPI := func() {
+		keystoneAPI := &keystonev1.KeystoneAPI{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "keystone",
+				Namespace: namespace,
+			},
+			Spec: keystonev1.KeystoneAPISpec{
+				DatabaseInstance: "openstack",
+				ContainerImage:   "quay.io/tripleozedcentos9/openstack-keystone:current-tripleo",
+				Secret:           "osp-secret",
+			},
+		}
+		Expect(k8sClient.Create(ctx, keystoneAPI)).To(Succeed())
+		keystoneAPI.Status.APIEndpoints = map[string]string{
+			"public": "http://keystone-public-openstack.apps-crc.testing",
+		}
+		Expect(k8sClient.Status().Update(ctx, keystoneAPI)).To(Succeed())
+	}
+
+	// the mariadb-operator exposes the DB via a service labeled for the database lib
+	createDBService := func() {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "openstack",
+				Namespace: namespace,
+				Labels: map[string]string{
+					"app": "mariadb",
+					"cr":  "mariadb-openstack",
+				},
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Port: 3306}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, svc)).To(Succeed())
+	}
+
+	simulateDBCompleted := func() {
+		db := &mariadbv1.MariaDBDatabase{}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, octaviaName, db)
+		}, timeout, interval).Should(Succeed())
+		db.Status.Completed = true
+		Expect(k8sClient.Status().Update(ctx, db)).To(Succeed())
+	}
+
+	simulateDBSyncSucceeded := func() {
+		job := &batchv1.Job{}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: octavia.ServiceName + "-db-sync", Namespace: namespace}, job)
+		}, timeout, interval).Should(Succeed())
+		job.Status.Succeeded = 1
+		Expect(k8sClient.Status().Update(ctx, job)).To(Succeed())
+	}
+
+	simulateKeystoneServiceReady := func() {
+		ksSvc := &keystonev1.KeystoneService{}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: octavia.ServiceName, Namespace: namespace}, ksSvc)
+		}, timeout, interval).Should(Succeed())
+		ksSvc.Status.ServiceID = "service-id"
+		ksSvc.Status.Conditions = condition.CreateList(
+			condition.TrueCondition(condition.ReadyCondition, condition.ReadyMessage))
+		Expect(k8sClient.Status().Update(ctx, ksSvc)).To(Succeed())
+	}
+
+	simulateDeploymentReady := func() {
+		depl := &appsv1.Deployment{}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: octavia.ServiceName, Namespace: namespace}, depl)
+		}, timeout, interval).Should(Succeed())
+		depl.Status.ObservedGeneration = depl.Generation
+		depl.Status.Replicas = 1
+		depl.Status.UpdatedReplicas = 1
+		depl.Status.AvailableReplicas = 1
+		depl.Status.ReadyReplicas = 1
+		Expect(k8sClient.Status().Update(ctx, depl)).To(Succeed())
+	}
+
+	// simulateReady - walks the OctaviaAPI through all the phases up to Ready with one replica
+	simulateReady := func() {
+		createSecret()
+		createKeystoneAPI()
+		createDBService()
+		simulateDBCompleted()
+		simulateDBSyncSucceeded()
+		simulateKeystoneServiceReady()
+		simulateDeploymentReady()
+		Eventually(conditionStatus(condition.ReadyCondition), timeout, interval).Should(Equal(corev1.ConditionTrue))
+	}
+
+	getDeploymentReplicas := func() int32 {
+		depl := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, apiName, depl)).To(Succeed())
+		return *depl.Spec.Replicas
+	}
+
+	BeforeEach(func() {
+		namespace = createNamespace()
+		octaviaName = types.NamespacedName{Name: "octavia", Namespace: namespace}
+		apiName = types.NamespacedName{Name: octavia.ServiceName, Namespace: namespace}
+
+		instance := &octaviav1.OctaviaAPI{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      octaviaName.Name,
+				Namespace: namespace,
+			},
+			Spec: octaviav1.OctaviaAPISpec{
+				DatabaseInstance: "openstack",
+				ContainerImage:   "quay.io/tripleozedcentos9/openstack-octavia-api:current-tripleo",
+				Secret:           "osp-secret",
+			},
+		}
+		Expect(k8sClient.Create(ctx, instance)).To(Succeed())
+	})
+
+	It("initializes the conditions and adds the finalizer", func() {
+		Eventually(func() []string {
+			return getOctaviaAPI().Finalizers
+		}, timeout, interval).Should(ContainElement("OctaviaAPI"))
+
+		instance := getOctaviaAPI()
+		for _, t := range []condition.Type{
+			condition.DBReadyCondition,
+			condition.DBSyncReadyCondition,
+			condition.ExposeServiceReadyCondition,
+			condition.ServiceConfigReadyCondition,
+			condition.DeploymentReadyCondition,
+		} {
+			Expect(instance.Status.Conditions.Has(t)).To(BeTrue(), string(t))
+		}
+	})
+
+	It("waits for the OpenStack secret and the KeystoneAPI", func() {
+		Eventually(conditionStatus(condition.InputReadyCondition), timeout, interval).Should(Equal(corev1.ConditionFalse))
+		ready := getOctaviaAPI().Status.Conditions.Get(condition.ReadyCondition)
+		Expect(ready.Status).To(Equal(corev1.ConditionFalse))
+		Expect(ready.Message).To(Equal(condition.InputReadyWaitingMessage))
+
+		createSecret()
+		createKeystoneAPI()
+
+		Eventually(conditionStatus(condition.InputReadyCondition), timeout, interval).Should(Equal(corev1.ConditionTrue))
+		Eventually(conditionStatus(condition.ServiceConfigReadyCondition), timeout, interval).Should(Equal(corev1.ConditionTrue))
+
+		cm := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "octavia-config-data", Namespace: namespace}, cm)).To(Succeed())
+		Expect(cm.Data).To(HaveKey("octavia.conf"))
+		Expect(cm.Data).To(HaveKey("httpd.conf"))
+		Expect(cm.Data["octavia.conf"]).To(ContainSubstring("auth_url=http://keystone-public-openstack.apps-crc.testing"))
+		// the envtest API server uses an IPv4 service network
+		Expect(cm.Data["octavia.conf"]).To(ContainSubstring("bind_host=0.0.0.0"))
+	})
+
+	It("deploys the API once the database and keystone are ready", func() {
+		createSecret()
+		createKeystoneAPI()
+		createDBService()
+
+		By("waiting for the MariaDBDatabase")
+		Eventually(conditionStatus(condition.DBReadyCondition), timeout, interval).Should(Equal(corev1.ConditionFalse))
+		simulateDBCompleted()
+		Eventually(conditionStatus(condition.DBReadyCondition), timeout, interval).Should(Equal(corev1.ConditionTrue))
+		Expect(getOctaviaAPI().Status.DatabaseHostname).To(Equal("openstack"))
+
+		By("running the db-sync job")
+		Eventually(conditionStatus(condition.DBSyncReadyCondition), timeout, interval).Should(Equal(corev1.ConditionFalse))
+		simulateDBSyncSucceeded()
+		Eventually(conditionStatus(condition.DBSyncReadyCondition), timeout, interval).Should(Equal(corev1.ConditionTrue))
+		Expect(getOctaviaAPI().Status.Hash).To(HaveKey(octaviav1.DbSyncHash))
+
+		By("registering the service in keystone")
+		simulateKeystoneServiceReady()
+		Eventually(conditionStatus(condition.KeystoneServiceReadyCondition), timeout, interval).Should(Equal(corev1.ConditionTrue))
+		Eventually(func() string {
+			return getOctaviaAPI().Status.ServiceID
+		}, timeout, interval).Should(Equal("service-id"))
+
+		By("exposing the endpoints")
+		Eventually(conditionStatus(condition.ExposeServiceReadyCondition), timeout, interval).Should(Equal(corev1.ConditionTrue))
+		Expect(getOctaviaAPI().Status.APIEndpoints).To(HaveKey("admin"))
+		Expect(getOctaviaAPI().Status.APIEndpoints).To(HaveKey("internal"))
+		Expect(getOctaviaAPI().Status.APIEndpoints).To(HaveKey("public"))
+		for _, name := range []string{"octavia-admin", "octavia-internal", "octavia-public"} {
+			svc := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, svc)).To(Succeed())
+			Expect(svc.Spec.Ports[0].Port).To(Equal(octavia.OctaviaDefaultPort))
+		}
+
+		By("creating the deployment")
+		Eventually(conditionStatus(condition.DeploymentReadyCondition), timeout, interval).Should(Equal(corev1.ConditionFalse))
+		simulateDeploymentReady()
+		Eventually(conditionStatus(condition.DeploymentReadyCondition), timeout, interval).Should(Equal(corev1.ConditionTrue))
+		Eventually(conditionStatus(condition.ReadyCondition), timeout, interval).Should(Equal(corev1.ConditionTrue))
+		Expect(getOctaviaAPI().Status.ReadyCount).To(Equal(int32(1)))
+		Expect(getOctaviaAPI().Status.ObservedGeneration).To(Equal(getOctaviaAPI().Generation))
+	})
+
+	It("schedules the DB purge", func() {
+		simulateReady()
+
+		cronJob := &batchv1.CronJob{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "octavia-db-purge", Namespace: namespace}, cronJob)).To(Succeed())
+		Expect(cronJob.Spec.Schedule).To(Equal("1 0 * * *"))
+		Expect(*cronJob.Spec.SuccessfulJobsHistoryLimit).To(Equal(int32(0)))
+	})
+
+	It("emits an event per phase transition", func() {
+		simulateReady()
+
+		Eventually(func() []string {
+			events := &corev1.EventList{}
+			Expect(k8sClient.List(ctx, events, client.InNamespace(namespace))).To(Succeed())
+			reasons := []string{}
+			for _, e := range events.Items {
+				if e.InvolvedObject.Name == octaviaName.Name && e.Type == corev1.EventTypeNormal {
+					reasons = append(reasons, e.Reason)
+				}
+			}
+			return reasons
+		}, timeout, interval).Should(ContainElements(
+			string(condition.DBReadyCondition),
+			string(condition.DBSyncReadyCondition),
+			string(condition.DeploymentReadyCondition),
+			string(condition.ReadyCondition)))
+	})
+
+	It("manages a PodDisruptionBudget for multiple replicas", func() {
+		simulateReady()
+		Expect(k8sClient.Get(ctx, apiName, &policyv1.PodDisruptionBudget{})).NotTo(Succeed())
+
+		instance := getOctaviaAPI()
+		instance.Spec.Replicas = 3
+		Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+		Eventually(func() int32 {
+			pdb := &policyv1.PodDisruptionBudget{}
+			if err := k8sClient.Get(ctx, apiName, pdb); err != nil {
+				return 0
+			}
+			return pdb.Spec.MaxUnavailable.IntVal
+		}, timeout, interval).Should(Equal(int32(1)))
+	})
+
+	It("applies the service overrides and disables the public route", func() {
+		simulateReady()
+
+		routeEnabled := false
+		instance := getOctaviaAPI()
+		instance.Spec.Override = octaviav1.OctaviaOverride{
+			Service: map[endpoint.Endpoint]octaviav1.OctaviaServiceOverride{
+				endpoint.EndpointInternal: {
+					Type:        corev1.ServiceTypeNodePort,
+					Annotations: map[string]string{"metallb.universe.tf/address-pool": "internalapi"},
+				},
+			},
+			PublicRoute: octaviav1.OctaviaRouteOverride{Enabled: &routeEnabled},
+		}
+		Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+		Eventually(func() map[string]string {
+			return getOctaviaAPI().Status.APIEndpoints
+		}, timeout, interval).Should(And(
+			HaveKeyWithValue("internal", fmt.Sprintf("http://octavia-internal.%s.svc:%d", namespace, octavia.OctaviaDefaultPort)),
+			HaveKeyWithValue("public", fmt.Sprintf("http://octavia-public.%s.svc:%d", namespace, octavia.OctaviaDefaultPort)),
+		))
+		internalSvc := &corev1.Service{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "octavia-internal", Namespace: namespace}, internalSvc)).To(Succeed())
+		Expect(internalSvc.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+		Expect(internalSvc.Annotations).To(HaveKeyWithValue("metallb.universe.tf/address-pool", "internalapi"))
+	})
+
+	It("hands the replicas over to the autoscaler", func() {
+		simulateReady()
+
+		instance := getOctaviaAPI()
+		instance.Spec.Autoscaling = octaviav1.OctaviaAutoscaling{
+			Enabled:     true,
+			MinReplicas: 2,
+			MaxReplicas: 4,
+		}
+		Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+		Eventually(func() error {
+			return k8sClient.Get(ctx, apiName, &autoscalingv2.HorizontalPodAutoscaler{})
+		}, timeout, interval).Should(Succeed())
+
+		// simulate the HPA scaling up, the operator must not reset the replicas
+		depl := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, apiName, depl)).To(Succeed())
+		replicas := int32(4)
+		depl.Spec.Replicas = &replicas
+		Expect(k8sClient.Update(ctx, depl)).To(Succeed())
+		instance = getOctaviaAPI()
+		instance.Spec.CustomServiceConfig = "[DEFAULT]\ndebug=true"
+		Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+		Consistently(getDeploymentReplicas, time.Second*2, interval).Should(Equal(int32(4)))
+	})
+
+	It("scales to zero in maintenance mode", func() {
+		simulateReady()
+
+		instance := getOctaviaAPI()
+		instance.Spec.Replicas = 3
+		instance.Spec.Autoscaling = octaviav1.OctaviaAutoscaling{
+			Enabled:     true,
+			MinReplicas: 2,
+			MaxReplicas: 4,
+		}
+		Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+		Eventually(func() error {
+			return k8sClient.Get(ctx, apiName, &policyv1.PodDisruptionBudget{})
+		}, timeout, interval).Should(Succeed())
+		Eventually(func() error {
+			return k8sClient.Get(ctx, apiName, &autoscalingv2.HorizontalPodAutoscaler{})
+		}, timeout, interval).Should(Succeed())
+
+		instance = getOctaviaAPI()
+		instance.Spec.Maintenance = true
+		Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+		Eventually(getDeploymentReplicas, timeout, interval).Should(Equal(int32(0)))
+		Eventually(func() string {
+			return string(getOctaviaAPI().Status.Conditions.Get(condition.DeploymentReadyCondition).Reason)
+		}, timeout, interval).Should(Equal(octaviav1.MaintenanceReason))
+		Expect(getOctaviaAPI().Status.Conditions.IsTrue(condition.KeystoneServiceReadyCondition)).To(BeTrue())
+		Eventually(func() bool {
+			return k8s_errors.IsNotFound(k8sClient.Get(ctx, apiName, &policyv1.PodDisruptionBudget{}))
+		}, timeout, interval).Should(BeTrue())
+		Eventually(func() bool {
+			return k8s_errors.IsNotFound(k8sClient.Get(ctx, apiName, &autoscalingv2.HorizontalPodAutoscaler{}))
+		}, timeout, interval).Should(BeTrue())
+	})
+
+	It("configures the token cache once the Memcached instance is ready", func() {
+		createSecret()
+		createKeystoneAPI()
+
+		instance := getOctaviaAPI()
+		instance.Spec.MemcachedInstance = "memcached"
+		Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+		Eventually(conditionStatus(octaviav1.MemcachedReadyCondition), timeout, interval).Should(Equal(corev1.ConditionFalse))
+
+		memcached := &unstructured.Unstructured{}
+		memcached.SetGroupVersionKind(schema.GroupVersionKind{
+			Group:   "memcached.openstack.org",
+			Version: "v1beta1",
+			Kind:    "Memcached",
+		})
+		memcached.SetName("memcached")
+		memcached.SetNamespace(namespace)
+		Expect(k8sClient.Create(ctx, memcached)).To(Succeed())
+		Expect(unstructured.SetNestedField(memcached.Object, map[string]interface{}{
+			"serverList": []interface{}{"memcached-0.memcached:11211", "memcached-1.memcached:11211"},
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+		}, "status")).To(Succeed())
+		Expect(k8sClient.Status().Update(ctx, memcached)).To(Succeed())
+
+		Eventually(conditionStatus(octaviav1.MemcachedReadyCondition), timeout, interval).Should(Equal(corev1.ConditionTrue))
+		Eventually(func() string {
+			cm := &corev1.ConfigMap{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "octavia-config-data", Namespace: namespace}, cm); err != nil {
+				return ""
+			}
+			return cm.Data["octavia.conf"]
+		}, timeout, interval).Should(ContainSubstring(
+			"memcached_servers=memcached-0.memcached:11211,memcached-1.memcached:11211"))
+
+		By("updating the servers when the Memcached instance changes")
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(memcached), memcached)).To(Succeed())
+		Expect(unstructured.SetNestedStringSlice(memcached.Object,
+			[]string{"memcached-0.memcached:11211"}, "status", "serverList")).To(Succeed())
+		Expect(k8sClient.Status().Update(ctx, memcached)).To(Succeed())
+		Eventually(func() string {
+			cm := &corev1.ConfigMap{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "octavia-config-data", Namespace: namespace}, cm); err != nil {
+				return ""
+			}
+			return cm.Data["octavia.conf"]
+		}, timeout, interval).Should(ContainSubstring("memcached_servers=memcached-0.memcached:11211\n"))
+	})
+
+	It("reports the db-sync job once it failed permanently", func() {
+		createSecret()
+		createKeystoneAPI()
+		createDBService()
+		simulateDBCompleted()
+
+		job := &batchv1.Job{}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: octavia.ServiceName + "-db-sync", Namespace: namespace}, job)
+		}, timeout, interval).Should(Succeed())
+		Expect(*job.Spec.BackoffLimit).To(BeEquivalentTo(6))
+		Expect(job.Spec.Template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+
+		By("waiting while failed pods get retried")
+		job.Status.Failed = 1
+		Expect(k8sClient.Status().Update(ctx, job)).To(Succeed())
+		Consistently(func() string {
+			c := getOctaviaAPI().Status.Conditions.Get(condition.DBSyncReadyCondition)
+			if c == nil {
+				return ""
+			}
+			return string(c.Reason)
+		}, time.Second, interval).Should(Equal(string(condition.RequestedReason)))
+
+		By("reporting the failure once the backoff limit is exceeded")
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: namespace}, job)).To(Succeed())
+		job.Status.Conditions = []batchv1.JobCondition{
+			{
+				Type:    batchv1.JobFailed,
+				Status:  corev1.ConditionTrue,
+				Reason:  "BackoffLimitExceeded",
+				Message: "Job has reached the specified backoff limit",
+			},
+		}
+		Expect(k8sClient.Status().Update(ctx, job)).To(Succeed())
+		Eventually(func() string {
+			c := getOctaviaAPI().Status.Conditions.Get(condition.DBSyncReadyCondition)
+			if c == nil || c.Reason != condition.ErrorReason {
+				return ""
+			}
+			return c.Message
+		}, timeout, interval).Should(ContainSubstring("BackoffLimitExceeded: Job has reached the specified backoff limit"))
+	})
+
+	It("dumps the DB before upgrading it to a new image", func() {
+		createSecret()
+		createKeystoneAPI()
+		createDBService()
+		simulateDBCompleted()
+		simulateDBSyncSucceeded()
+		Eventually(conditionStatus(condition.DBSyncReadyCondition), timeout, interval).Should(Equal(corev1.ConditionTrue))
+		Eventually(func() string {
+			return getOctaviaAPI().Status.DBSyncImage
+		}, timeout, interval).ShouldNot(BeEmpty())
+
+		instance := getOctaviaAPI()
+		instance.Spec.PreUpgradeBackup.Enabled = true
+		instance.Spec.PreUpgradeBackup.PersistentVolumeClaim = "octavia-backup"
+		instance.Spec.ContainerImage = "quay.io/tripleozedcentos9/openstack-octavia-api:new"
+		Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+
+		By("running the backup before the db-sync")
+		Eventually(conditionStatus(octaviav1.PreUpgradeBackupReadyCondition), timeout, interval).Should(Equal(corev1.ConditionFalse))
+		backupStatus := getOctaviaAPI().Status.PreUpgradeBackup
+		Expect(backupStatus).NotTo(BeNil())
+		Expect(backupStatus.Location).To(HavePrefix("pvc://octavia-backup/octavia-"))
+		backupJobName := types.NamespacedName{
+			Name:      "octavia-pre-upgrade-" + backupStatus.StartTime.UTC().Format("20060102150405"),
+			Namespace: namespace,
+		}
+		job := &batchv1.Job{}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, backupJobName, job)
+		}, timeout, interval).Should(Succeed())
+		Consistently(func() bool {
+			return k8s_errors.IsNotFound(k8sClient.Get(ctx,
+				types.NamespacedName{Name: octavia.ServiceName + "-db-sync", Namespace: namespace}, &batchv1.Job{}))
+		}, time.Second, interval).Should(BeTrue())
+
+		By("refusing the upgrade if the backup failed")
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"},
+		}
+		Expect(k8sClient.Status().Update(ctx, job)).To(Succeed())
+		Eventually(func() string {
+			c := getOctaviaAPI().Status.Conditions.Get(octaviav1.PreUpgradeBackupReadyCondition)
+			if c == nil {
+				return ""
+			}
+			return c.Message
+		}, timeout, interval).Should(ContainSubstring("BackoffLimitExceeded"))
+		Expect(k8s_errors.IsNotFound(k8sClient.Get(ctx,
+			types.NamespacedName{Name: octavia.ServiceName + "-db-sync", Namespace: namespace}, &batchv1.Job{}))).To(BeTrue())
+
+		By("upgrading the DB if the backup gets skipped")
+		instance = getOctaviaAPI()
+		instance.SetAnnotations(map[string]string{octaviav1.SkipPreUpgradeBackupAnnotation: "true"})
+		Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+		Eventually(func() string {
+			dbSync := &batchv1.Job{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: octavia.ServiceName + "-db-sync", Namespace: namespace}, dbSync)
+			if err != nil {
+				return ""
+			}
+			return dbSync.Spec.Template.Spec.Containers[0].Image
+		}, timeout, interval).Should(Equal("quay.io/tripleozedcentos9/openstack-octavia-api:new"))
+	})
+
+	It("only reports the status while the reconcile is paused", func() {
+		Eventually(func() []string {
+			return getOctaviaAPI().Finalizers
+		}, timeout, interval).Should(ContainElement("OctaviaAPI"))
+
+		instance := getOctaviaAPI()
+		instance.SetAnnotations(map[string]string{octaviav1.ReconcilePausedAnnotation: "true"})
+		Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+		Eventually(conditionStatus(octaviav1.ReconcilePausedCondition), timeout, interval).Should(Equal(corev1.ConditionTrue))
+
+		createSecret()
+		createKeystoneAPI()
+		Consistently(conditionStatus(condition.InputReadyCondition), time.Second*2, interval).ShouldNot(Equal(corev1.ConditionTrue))
+
+		By("resuming the reconcile")
+		instance = getOctaviaAPI()
+		instance.SetAnnotations(nil)
+		Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+		Eventually(func() bool {
+			return getOctaviaAPI().Status.Conditions.Has(octaviav1.ReconcilePausedCondition)
+		}, timeout, interval).Should(BeFalse())
+		Eventually(conditionStatus(condition.InputReadyCondition), timeout, interval).Should(Equal(corev1.ConditionTrue))
+	})
+
+	It("removes the finalizers on delete", func() {
+		createSecret()
+		createKeystoneAPI()
+		createDBService()
+		simulateDBCompleted()
+		simulateDBSyncSucceeded()
+
+		ksSvc := &keystonev1.KeystoneService{}
+		Eventually(func() []string {
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: octavia.ServiceName, Namespace: namespace}, ksSvc)
+			if err != nil {
+				return nil
+			}
+			return ksSvc.Finalizers
+		}, timeout, interval).Should(ContainElement("OctaviaAPI"))
+
+		Expect(k8sClient.Delete(ctx, getOctaviaAPI())).To(Succeed())
+
+		Eventually(func() bool {
+			err := k8sClient.Get(ctx, octaviaName, &octaviav1.OctaviaAPI{})
+			return k8s_errors.IsNotFound(err)
+		}, timeout, interval).Should(BeTrue())
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(ksSvc), ksSvc)).To(Succeed())
+		Expect(ksSvc.Finalizers).NotTo(ContainElement("OctaviaAPI"))
+	})
+
+	It("revokes the application credentials on delete", func() {
+		server := octaviatest.NewServer()
+		defer server.Close()
+
+		createSecret()
+		createKeystoneAPI()
+		keystoneAPI := &keystonev1.KeystoneAPI{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "keystone", Namespace: namespace}, keystoneAPI)).To(Succeed())
+		keystoneAPI.Status.APIEndpoints = map[string]string{"public": server.IdentityURL()}
+		Expect(k8sClient.Status().Update(ctx, keystoneAPI)).To(Succeed())
+
+		instance := getOctaviaAPI()
+		instance.Spec.ApplicationCredential.Enabled = true
+		Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+
+		createDBService()
+		simulateDBCompleted()
+		simulateDBSyncSucceeded()
+		simulateKeystoneServiceReady()
+		Eventually(conditionStatus(octaviav1.ApplicationCredentialReadyCondition), timeout, interval).Should(Equal(corev1.ConditionTrue))
+		Expect(server.ApplicationCredentials()).To(HaveLen(1))
+
+		Expect(k8sClient.Delete(ctx, getOctaviaAPI())).To(Succeed())
+		Eventually(func() bool {
+			return k8s_errors.IsNotFound(k8sClient.Get(ctx, octaviaName, &octaviav1.OctaviaAPI{}))
+		}, timeout, interval).Should(BeTrue())
+		Expect(server.ApplicationCredentials()).To(BeEmpty())
+	})
+})
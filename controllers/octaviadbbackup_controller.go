@@ -0,0 +1,208 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/openstack-k8s-operators/lib-common/modules/common"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia"
+
+	batchv1 "k8s.io/api/batch/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OctaviaDBBackupReconciler reconciles a OctaviaDBBackup object
+type OctaviaDBBackupReconciler struct {
+	client.Client
+	Kclient kubernetes.Interface
+	Log     logr.Logger
+	Scheme  *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=octavia.openstack.org,resources=octaviadbbackups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=octavia.openstack.org,resources=octaviadbbackups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=octavia.openstack.org,resources=octaviadbbackups/finalizers,verbs=update
+
+// Reconcile - runs the job dumping the Octavia DB once
+func (r *OctaviaDBBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	_ = r.Log.WithValues("octaviadbbackup", req.NamespacedName)
+
+	instance := &octaviav1.OctaviaDBBackup{}
+	err := r.Client.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	//
+	// initialize status
+	//
+	if instance.Status.Conditions == nil {
+		instance.Status.Conditions = condition.Conditions{}
+
+		cl := condition.CreateList(
+			condition.UnknownCondition(octaviav1.OctaviaAPIReadyCondition, condition.InitReason, octaviav1.OctaviaAPIReadyInitMessage),
+			condition.UnknownCondition(octaviav1.DBBackupReadyCondition, condition.InitReason, octaviav1.DBBackupReadyInitMessage))
+
+		instance.Status.Conditions.Init(&cl)
+
+		// Register overall status immediately to have an early feedback e.g. in the cli
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	helper, err := helper.NewHelper(
+		instance,
+		r.Client,
+		r.Kclient,
+		r.Scheme,
+		r.Log,
+	)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always patch the instance status when exiting this function so we can persist any changes.
+	defer func() {
+		// update the overall status condition if service is ready
+		if instance.IsReady() {
+			instance.Status.Conditions.MarkTrue(condition.ReadyCondition, condition.ReadyMessage)
+		}
+
+		if err := helper.SetAfter(instance); err != nil {
+			util.LogErrorForObject(helper, err, "Set after and calc patch/diff", instance)
+		}
+
+		if changed := helper.GetChanges()["status"]; changed {
+			patch := client.MergeFrom(helper.GetBeforeObject())
+
+			if err := r.Status().Patch(ctx, instance, patch); err != nil && !k8s_errors.IsNotFound(err) {
+				util.LogErrorForObject(helper, err, "Update status", instance)
+			}
+		}
+	}()
+
+	// the dump is owned by the target and its retention, nothing to clean up on delete
+	if !instance.DeletionTimestamp.IsZero() || instance.IsReady() {
+		return ctrl.Result{}, nil
+	}
+
+	return r.reconcileNormal(ctx, instance, helper)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OctaviaDBBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&octaviav1.OctaviaDBBackup{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
+
+func (r *OctaviaDBBackupReconciler) reconcileNormal(
+	ctx context.Context,
+	instance *octaviav1.OctaviaDBBackup,
+	helper *helper.Helper,
+) (ctrl.Result, error) {
+	util.LogForObject(helper, "Reconciling DB backup", instance)
+
+	if err := octavia.ValidateDBBackupTarget(instance.Spec.Target); err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.DBBackupReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			octaviav1.DBBackupReadyErrorMessage,
+			err.Error()))
+		// a spec update triggers the next reconcile
+		return ctrl.Result{}, nil
+	}
+
+	octaviaAPI, ctrlResult, err := getOctaviaAPIWithDB(ctx, helper, instance.Namespace, &instance.Status.Conditions)
+	if err != nil {
+		return ctrlResult, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+
+	serviceLabels := map[string]string{
+		common.AppSelector: octavia.ServiceName,
+	}
+	job, err := ensureOneShotJob(ctx, helper, instance, octavia.DBBackupJob(instance, octaviaAPI, serviceLabels))
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.DBBackupReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			octaviav1.DBBackupReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+
+	// the job watch triggers a reconcile once it finished
+	finished, failure := getJobResult(job)
+	if !finished {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.DBBackupReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			octaviav1.DBBackupReadyRunningMessage))
+		return ctrl.Result{}, nil
+	}
+	if failure != "" {
+		// the failed job is kept to check the logs, deleting it runs the backup again
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.DBBackupReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			octaviav1.DBBackupReadyErrorMessage,
+			fmt.Sprintf("job %s failed, %s", job.Name, failure)))
+		return ctrl.Result{}, nil
+	}
+
+	instance.Status.FileName = octavia.DBBackupFileName(instance)
+	instance.Status.Location = octavia.DBBackupLocation(instance.Spec.Target, instance.Status.FileName)
+	instance.Status.CompletionTime = job.Status.CompletionTime
+	if instance.Status.CompletionTime == nil {
+		now := metav1.Now()
+		instance.Status.CompletionTime = &now
+	}
+	instance.Status.Conditions.MarkTrue(
+		octaviav1.DBBackupReadyCondition,
+		octaviav1.DBBackupReadyMessage,
+		instance.Status.Location)
+
+	if err := deleteFinishedJob(ctx, helper, job, octaviaAPI.Spec.PreserveJobs); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	util.LogForObject(helper, fmt.Sprintf("Reconciled DB backup %s successfully", instance.Status.Location), instance)
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("OctaviaDBBackup and OctaviaDBRestore controllers", func() {
+	var namespace string
+
+	// createSyncedOctaviaAPI - an OctaviaAPI with a synced DB
+	createSyncedOctaviaAPI := func() {
+		createOctaviaAPIStub(namespace, nil,
+			condition.TrueCondition(condition.DBSyncReadyCondition, condition.DBSyncReadyMessage))
+	}
+
+	// restoreAnnotation - the restore which paused the OctaviaAPI
+	restoreAnnotation := func() string {
+		instance := &octaviav1.OctaviaAPI{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "octavia", Namespace: namespace}, instance)).To(Succeed())
+		return instance.GetAnnotations()[octaviav1.DBRestoreAnnotation]
+	}
+
+	// createAPIDeployment - the API deployment with the running pods reported in its status
+	createAPIDeployment := func(runningPods int32) *appsv1.Deployment {
+		labels := map[string]string{"service": octavia.ServiceName}
+		depl := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      octavia.ServiceName,
+				Namespace: namespace,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "octavia-api", Image: "octavia-api"}},
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, depl)).To(Succeed())
+		depl.Status.Replicas = runningPods
+		Expect(k8sClient.Status().Update(ctx, depl)).To(Succeed())
+		return depl
+	}
+
+	createBackup := func(name string, retention int) *octaviav1.OctaviaDBBackup {
+		backup := &octaviav1.OctaviaDBBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: octaviav1.OctaviaDBBackupSpec{
+				Target:    octaviav1.OctaviaDBBackupTarget{PersistentVolumeClaim: "octavia-backup"},
+				Retention: retention,
+			},
+		}
+		Expect(k8sClient.Create(ctx, backup)).To(Succeed())
+		return backup
+	}
+
+	createRestore := func(name string) *octaviav1.OctaviaDBRestore {
+		restore := &octaviav1.OctaviaDBRestore{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: octaviav1.OctaviaDBRestoreSpec{
+				Backup: "backup",
+			},
+		}
+		Expect(k8sClient.Create(ctx, restore)).To(Succeed())
+		return restore
+	}
+
+	getJob := func(name string) *batchv1.Job {
+		job := &batchv1.Job{}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, job)
+		}, timeout, interval).Should(Succeed())
+		return job
+	}
+
+	jobExists := func(name string) func() bool {
+		return func() bool {
+			return !k8s_errors.IsNotFound(k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &batchv1.Job{}))
+		}
+	}
+
+	// finishJob - simulates the job controller marking the job completed or failed
+	finishJob := func(name string, jobCondition batchv1.JobConditionType) {
+		job := getJob(name)
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: jobCondition, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"},
+		}
+		if jobCondition == batchv1.JobComplete {
+			now := metav1.Now()
+			job.Status.Conditions[0].Reason = ""
+			job.Status.StartTime = &now
+			job.Status.CompletionTime = &now
+			job.Status.Succeeded = 1
+		} else {
+			job.Status.Failed = 1
+		}
+		Expect(k8sClient.Status().Update(ctx, job)).To(Succeed())
+	}
+
+	// createReadyBackup - the backup "backup" with a completed dump
+	createReadyBackup := func() *octaviav1.OctaviaDBBackup {
+		backup := createBackup("backup", 0)
+		finishJob("backup-db-backup", batchv1.JobComplete)
+		Eventually(isReady(backup), timeout, interval).Should(BeTrue())
+		return backup
+	}
+
+	BeforeEach(func() {
+		namespace = createNamespace()
+	})
+
+	It("dumps the DB once the OctaviaAPI synced it", func() {
+		backup := createBackup("backup", 3)
+		Eventually(getCondition(backup, &backup.Status.Conditions, octaviav1.OctaviaAPIReadyCondition), timeout, interval).
+			Should(HaveField("Reason", condition.RequestedReason))
+		Expect(jobExists("backup-db-backup")()).To(BeFalse())
+
+		createSyncedOctaviaAPI()
+		job := getJob("backup-db-backup")
+		Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "Retention", Value: "3"}))
+		Expect(job.Spec.Template.Labels).To(Equal(octavia.JobPodLabels(octavia.DBBackupComponent)))
+		Eventually(getCondition(backup, &backup.Status.Conditions, octaviav1.DBBackupReadyCondition), timeout, interval).
+			Should(HaveField("Reason", condition.RequestedReason))
+
+		finishJob(job.Name, batchv1.JobComplete)
+		Eventually(isReady(backup), timeout, interval).Should(BeTrue())
+		Expect(backup.Status.FileName).To(Equal(octavia.DBBackupFileName(backup)))
+		Expect(backup.Status.Location).To(Equal("pvc://octavia-backup/" + backup.Status.FileName))
+		Eventually(jobExists(job.Name), timeout, interval).Should(BeFalse())
+	})
+
+	It("keeps the default number of dumps", func() {
+		createSyncedOctaviaAPI()
+		createBackup("backup", 0)
+
+		job := getJob("backup-db-backup")
+		Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "Retention", Value: "7"}))
+	})
+
+	It("keeps the failed backup job", func() {
+		createSyncedOctaviaAPI()
+		backup := createBackup("backup", 0)
+
+		finishJob("backup-db-backup", batchv1.JobFailed)
+		Eventually(getCondition(backup, &backup.Status.Conditions, octaviav1.DBBackupReadyCondition), timeout, interval).
+			Should(And(
+				HaveField("Reason", condition.ErrorReason),
+				HaveField("Message", ContainSubstring("BackoffLimitExceeded"))))
+		Consistently(jobExists("backup-db-backup"), time.Second*2, interval).Should(BeTrue())
+		Expect(backup.IsReady()).To(BeFalse())
+	})
+
+	It("pauses the API while it restores the DB", func() {
+		createSyncedOctaviaAPI()
+		backup := createReadyBackup()
+		depl := createAPIDeployment(1)
+
+		restore := createRestore("restore")
+		Eventually(restoreAnnotation, timeout, interval).Should(Equal("restore"))
+		Eventually(getCondition(restore, &restore.Status.Conditions, octaviav1.APIPausedCondition), timeout, interval).
+			Should(HaveField("Reason", condition.RequestedReason))
+		Consistently(jobExists("restore-db-restore"), time.Second*2, interval).Should(BeFalse())
+
+		By("restoring once the API pods stopped")
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(depl), depl)).To(Succeed())
+		depl.Status.Replicas = 0
+		Expect(k8sClient.Status().Update(ctx, depl)).To(Succeed())
+		job := getJob("restore-db-restore")
+		Expect(job.Spec.Template.Spec.InitContainers[0].Name).To(Equal("db-restore"))
+		Eventually(getCondition(restore, &restore.Status.Conditions, octaviav1.APIPausedCondition), timeout, interval).
+			Should(HaveField("Status", corev1.ConditionTrue))
+		Expect(restore.Finalizers).To(ContainElement("OctaviaDBRestore"))
+
+		By("resuming the API once the DB got restored")
+		finishJob(job.Name, batchv1.JobComplete)
+		Eventually(isReady(restore), timeout, interval).Should(BeTrue())
+		Expect(restore.Status.Location).To(Equal(backup.Status.Location))
+		Eventually(restoreAnnotation, timeout, interval).Should(BeEmpty())
+	})
+
+	It("waits for a concurrent restore", func() {
+		createSyncedOctaviaAPI()
+		createReadyBackup()
+
+		first := createRestore("first")
+		getJob("first-db-restore")
+		Expect(restoreAnnotation()).To(Equal("first"))
+
+		second := createRestore("second")
+		Eventually(getCondition(second, &second.Status.Conditions, octaviav1.APIPausedCondition), timeout, interval).
+			Should(And(
+				HaveField("Reason", condition.RequestedReason),
+				HaveField("Message", ContainSubstring("first"))))
+		Consistently(jobExists("second-db-restore"), time.Second*2, interval).Should(BeFalse())
+		Expect(restoreAnnotation()).To(Equal("first"))
+
+		By("taking over once the first restore is gone")
+		Expect(k8sClient.Delete(ctx, first)).To(Succeed())
+		Eventually(restoreAnnotation, timeout, interval).Should(Equal("second"))
+		getJob("second-db-restore")
+	})
+
+	It("keeps the API paused after a failed restore", func() {
+		createSyncedOctaviaAPI()
+		createReadyBackup()
+
+		restore := createRestore("restore")
+		finishJob("restore-db-restore", batchv1.JobFailed)
+		Eventually(getCondition(restore, &restore.Status.Conditions, octaviav1.DBRestoreReadyCondition), timeout, interval).
+			Should(HaveField("Reason", condition.ErrorReason))
+		Consistently(restoreAnnotation, time.Second*2, interval).Should(Equal("restore"))
+
+		By("resuming the API once the restore got deleted")
+		Expect(k8sClient.Delete(ctx, restore)).To(Succeed())
+		Eventually(restoreAnnotation, timeout, interval).Should(BeEmpty())
+		Eventually(isDeleted(restore), timeout, interval).Should(BeTrue())
+	})
+})
@@ -0,0 +1,381 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/openstack-k8s-operators/lib-common/modules/common"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// apiPausedRequeue - time to wait for the API pods to stop
+const apiPausedRequeue = time.Second * 5
+
+// OctaviaDBRestoreReconciler reconciles a OctaviaDBRestore object
+type OctaviaDBRestoreReconciler struct {
+	client.Client
+	Kclient kubernetes.Interface
+	Log     logr.Logger
+	Scheme  *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=octavia.openstack.org,resources=octaviadbrestores,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=octavia.openstack.org,resources=octaviadbrestores/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=octavia.openstack.org,resources=octaviadbrestores/finalizers,verbs=update
+
+// Reconcile - pauses the API, restores the DB from the backup and syncs it once
+func (r *OctaviaDBRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	_ = r.Log.WithValues("octaviadbrestore", req.NamespacedName)
+
+	instance := &octaviav1.OctaviaDBRestore{}
+	err := r.Client.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	//
+	// initialize status
+	//
+	if instance.Status.Conditions == nil {
+		instance.Status.Conditions = condition.Conditions{}
+
+		cl := condition.CreateList(
+			condition.UnknownCondition(octaviav1.DBBackupReadyCondition, condition.InitReason, octaviav1.DBBackupReadyInitMessage),
+			condition.UnknownCondition(octaviav1.OctaviaAPIReadyCondition, condition.InitReason, octaviav1.OctaviaAPIReadyInitMessage),
+			condition.UnknownCondition(octaviav1.APIPausedCondition, condition.InitReason, octaviav1.APIPausedInitMessage),
+			condition.UnknownCondition(octaviav1.DBRestoreReadyCondition, condition.InitReason, octaviav1.DBRestoreReadyInitMessage))
+
+		instance.Status.Conditions.Init(&cl)
+
+		// Register overall status immediately to have an early feedback e.g. in the cli
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	helper, err := helper.NewHelper(
+		instance,
+		r.Client,
+		r.Kclient,
+		r.Scheme,
+		r.Log,
+	)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always patch the instance status when exiting this function so we can persist any changes.
+	defer func() {
+		// update the overall status condition if service is ready
+		if instance.IsReady() {
+			instance.Status.Conditions.MarkTrue(condition.ReadyCondition, condition.ReadyMessage)
+		}
+
+		if err := helper.SetAfter(instance); err != nil {
+			util.LogErrorForObject(helper, err, "Set after and calc patch/diff", instance)
+		}
+
+		if changed := helper.GetChanges()["status"]; changed {
+			patch := client.MergeFrom(helper.GetBeforeObject())
+
+			if err := r.Status().Patch(ctx, instance, patch); err != nil && !k8s_errors.IsNotFound(err) {
+				util.LogErrorForObject(helper, err, "Update status", instance)
+			}
+		}
+	}()
+
+	// Handle restore delete
+	if !instance.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, instance, helper)
+	}
+
+	// Handle non-deleted restores
+	return r.reconcileNormal(ctx, instance, helper)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OctaviaDBRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// reconcile all restores referencing a backup when the backup changes
+	backupFn := func(o client.Object) []reconcile.Request {
+		result := []reconcile.Request{}
+
+		restores := &octaviav1.OctaviaDBRestoreList{}
+		listOpts := []client.ListOption{
+			client.InNamespace(o.GetNamespace()),
+		}
+		if err := r.Client.List(context.Background(), restores, listOpts...); err != nil {
+			r.Log.Error(err, "Unable to retrieve OctaviaDBRestore CRs")
+			return nil
+		}
+		for _, restore := range restores.Items {
+			if restore.Spec.Backup == o.GetName() {
+				name := client.ObjectKey{
+					Namespace: o.GetNamespace(),
+					Name:      restore.Name,
+				}
+				r.Log.Info(fmt.Sprintf("OctaviaDBBackup %s is used by OctaviaDBRestore CR %s", o.GetName(), restore.Name))
+				result = append(result, reconcile.Request{NamespacedName: name})
+			}
+		}
+
+		return result
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&octaviav1.OctaviaDBRestore{}).
+		Owns(&batchv1.Job{}).
+		Watches(&source.Kind{Type: &octaviav1.OctaviaDBBackup{}},
+			handler.EnqueueRequestsFromMapFunc(backupFn)).
+		Complete(r)
+}
+
+func (r *OctaviaDBRestoreReconciler) reconcileDelete(
+	ctx context.Context,
+	instance *octaviav1.OctaviaDBRestore,
+	helper *helper.Helper,
+) (ctrl.Result, error) {
+	util.LogForObject(helper, "Reconciling DB restore delete", instance)
+
+	// resume the API, also if the restore did not finish
+	if err := r.setRestoreAnnotation(ctx, helper, instance, false); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	controllerutil.RemoveFinalizer(instance, helper.GetFinalizer())
+	if err := r.Update(ctx, instance); err != nil && !k8s_errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	util.LogForObject(helper, "Reconciled DB restore delete successfully", instance)
+	return ctrl.Result{}, nil
+}
+
+func (r *OctaviaDBRestoreReconciler) reconcileNormal(
+	ctx context.Context,
+	instance *octaviav1.OctaviaDBRestore,
+	helper *helper.Helper,
+) (ctrl.Result, error) {
+	util.LogForObject(helper, "Reconciling DB restore", instance)
+
+	if instance.IsReady() {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(instance, helper.GetFinalizer()) {
+		// If the object doesn't have our finalizer, add it.
+		controllerutil.AddFinalizer(instance, helper.GetFinalizer())
+		// Register the finalizer immediately to not leave the API paused on delete
+		err := r.Update(ctx, instance)
+
+		return ctrl.Result{}, err
+	}
+
+	//
+	// get the backup to restore
+	//
+	backup := &octaviav1.OctaviaDBBackup{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: instance.Spec.Backup, Namespace: instance.Namespace}, backup)
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.DBBackupReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			octaviav1.DBBackupReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+	if err != nil || !backup.IsReady() {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.DBBackupReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			octaviav1.DBBackupReadyWaitingMessage,
+			instance.Spec.Backup))
+		// the backup watch triggers a reconcile once it completed
+		return ctrl.Result{}, nil
+	}
+	instance.Status.Location = backup.Status.Location
+	instance.Status.Conditions.MarkTrue(
+		octaviav1.DBBackupReadyCondition,
+		octaviav1.DBBackupReadyMessage,
+		backup.Status.Location)
+
+	octaviaAPI, ctrlResult, err := getOctaviaAPIWithDB(ctx, helper, instance.Namespace, &instance.Status.Conditions)
+	if err != nil {
+		return ctrlResult, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+
+	//
+	// pause the API, it scales to zero while the annotation is set
+	//
+	if owner := octaviaAPI.GetAnnotations()[octaviav1.DBRestoreAnnotation]; owner != "" && owner != instance.Name {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.APIPausedCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			octaviav1.APIPausedConflictMessage,
+			owner))
+		return ctrl.Result{RequeueAfter: octaviaClientRequeue}, nil
+	}
+	if err := r.setRestoreAnnotation(ctx, helper, instance, true); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	depl := &appsv1.Deployment{}
+	err = r.Client.Get(ctx, types.NamespacedName{Name: octavia.ServiceName, Namespace: instance.Namespace}, depl)
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	if err == nil && depl.Status.Replicas > 0 {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.APIPausedCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			octaviav1.APIPausedRunningMessage,
+			depl.Status.Replicas))
+		return ctrl.Result{RequeueAfter: apiPausedRequeue}, nil
+	}
+	instance.Status.Conditions.MarkTrue(octaviav1.APIPausedCondition, octaviav1.APIPausedMessage)
+
+	//
+	// restore and sync the DB
+	//
+	serviceLabels := map[string]string{
+		common.AppSelector: octavia.ServiceName,
+	}
+	job, err := ensureOneShotJob(ctx, helper, instance, octavia.DBRestoreJob(instance, backup, octaviaAPI, serviceLabels))
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.DBRestoreReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			octaviav1.DBRestoreReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+
+	// the job watch triggers a reconcile once it finished
+	finished, failure := getJobResult(job)
+	if !finished {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.DBRestoreReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			octaviav1.DBRestoreReadyRunningMessage))
+		return ctrl.Result{}, nil
+	}
+	if failure != "" {
+		// the API stays paused on the DB in unknown state. Deleting the failed job runs the
+		// restore again, deleting the OctaviaDBRestore resumes the API.
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.DBRestoreReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			octaviav1.DBRestoreReadyErrorMessage,
+			fmt.Sprintf("job %s failed, %s", job.Name, failure)))
+		return ctrl.Result{}, nil
+	}
+
+	// resume the API
+	if err := r.setRestoreAnnotation(ctx, helper, instance, false); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	instance.Status.CompletionTime = job.Status.CompletionTime
+	if instance.Status.CompletionTime == nil {
+		now := metav1.Now()
+		instance.Status.CompletionTime = &now
+	}
+	instance.Status.Conditions.MarkTrue(
+		octaviav1.DBRestoreReadyCondition,
+		octaviav1.DBRestoreReadyMessage,
+		backup.Status.Location)
+
+	if err := deleteFinishedJob(ctx, helper, job, octaviaAPI.Spec.PreserveJobs); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	util.LogForObject(helper, fmt.Sprintf("Reconciled DB restore from %s successfully", backup.Status.Location), instance)
+	return ctrl.Result{}, nil
+}
+
+// setRestoreAnnotation - sets or removes the DBRestoreAnnotation of the restore on the OctaviaAPI
+// in the namespace, which pauses the API. An annotation of another restore is kept.
+func (r *OctaviaDBRestoreReconciler) setRestoreAnnotation(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *octaviav1.OctaviaDBRestore,
+	paused bool,
+) error {
+	octaviaAPI, err := octaviav1.GetOctaviaAPI(ctx, h, instance.Namespace, map[string]string{})
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	owner, found := octaviaAPI.GetAnnotations()[octaviav1.DBRestoreAnnotation]
+	if paused == found || (found && owner != instance.Name) {
+		return nil
+	}
+
+	patch := client.MergeFrom(octaviaAPI.DeepCopy())
+	annotations := octaviaAPI.GetAnnotations()
+	if paused {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[octaviav1.DBRestoreAnnotation] = instance.Name
+	} else {
+		delete(annotations, octaviav1.DBRestoreAnnotation)
+	}
+	octaviaAPI.SetAnnotations(annotations)
+	if err := r.Client.Patch(ctx, octaviaAPI, patch); err != nil {
+		return err
+	}
+
+	util.LogForObject(h, fmt.Sprintf("Set %s on OctaviaAPI %s: %v", octaviav1.DBRestoreAnnotation, octaviaAPI.Name, paused), instance)
+	return nil
+}
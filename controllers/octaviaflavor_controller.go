@@ -0,0 +1,401 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia"
+
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// OctaviaFlavorReconciler reconciles a OctaviaFlavor object
+type OctaviaFlavorReconciler struct {
+	client.Client
+	Kclient kubernetes.Interface
+	Log     logr.Logger
+	Scheme  *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=octavia.openstack.org,resources=octaviaflavors,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=octavia.openstack.org,resources=octaviaflavors/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=octavia.openstack.org,resources=octaviaflavors/finalizers,verbs=update
+
+// Reconcile - creates, updates and deletes the flavor in the Octavia API
+func (r *OctaviaFlavorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	_ = r.Log.WithValues("octaviaflavor", req.NamespacedName)
+
+	instance := &octaviav1.OctaviaFlavor{}
+	err := r.Client.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	//
+	// initialize status
+	//
+	if instance.Status.Conditions == nil {
+		instance.Status.Conditions = condition.Conditions{}
+
+		cl := condition.CreateList(
+			condition.UnknownCondition(octaviav1.OctaviaAPIReadyCondition, condition.InitReason, octaviav1.OctaviaAPIReadyInitMessage),
+			condition.UnknownCondition(octaviav1.FlavorProfileReadyCondition, condition.InitReason, octaviav1.FlavorProfileReadyInitMessage),
+			condition.UnknownCondition(octaviav1.FlavorReadyCondition, condition.InitReason, octaviav1.FlavorReadyInitMessage))
+
+		instance.Status.Conditions.Init(&cl)
+
+		// Register overall status immediately to have an early feedback e.g. in the cli
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	helper, err := helper.NewHelper(
+		instance,
+		r.Client,
+		r.Kclient,
+		r.Scheme,
+		r.Log,
+	)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always patch the instance status when exiting this function so we can persist any changes.
+	defer func() {
+		// update the overall status condition if service is ready
+		if instance.IsReady() {
+			instance.Status.Conditions.MarkTrue(condition.ReadyCondition, condition.ReadyMessage)
+		}
+
+		if err := helper.SetAfter(instance); err != nil {
+			util.LogErrorForObject(helper, err, "Set after and calc patch/diff", instance)
+		}
+
+		if changed := helper.GetChanges()["status"]; changed {
+			patch := client.MergeFrom(helper.GetBeforeObject())
+
+			if err := r.Status().Patch(ctx, instance, patch); err != nil && !k8s_errors.IsNotFound(err) {
+				util.LogErrorForObject(helper, err, "Update status", instance)
+			}
+		}
+	}()
+
+	// Handle flavor delete
+	if !instance.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, instance, helper)
+	}
+
+	// Handle non-deleted flavors
+	return r.reconcileNormal(ctx, instance, helper)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OctaviaFlavorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// reconcile all flavors referencing a flavor profile when the flavor profile changes
+	flavorProfileFn := func(o client.Object) []reconcile.Request {
+		result := []reconcile.Request{}
+
+		flavors := &octaviav1.OctaviaFlavorList{}
+		listOpts := []client.ListOption{
+			client.InNamespace(o.GetNamespace()),
+		}
+		if err := r.Client.List(context.Background(), flavors, listOpts...); err != nil {
+			r.Log.Error(err, "Unable to retrieve OctaviaFlavor CRs")
+			return nil
+		}
+
+		for _, f := range flavors.Items {
+			if f.Spec.FlavorProfile == o.GetName() {
+				name := client.ObjectKey{
+					Namespace: o.GetNamespace(),
+					Name:      f.Name,
+				}
+				r.Log.Info(fmt.Sprintf("OctaviaFlavorProfile %s is used by OctaviaFlavor CR %s", o.GetName(), f.Name))
+				result = append(result, reconcile.Request{NamespacedName: name})
+			}
+		}
+
+		return result
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&octaviav1.OctaviaFlavor{}).
+		Watches(&source.Kind{Type: &octaviav1.OctaviaFlavorProfile{}},
+			handler.EnqueueRequestsFromMapFunc(flavorProfileFn)).
+		Complete(r)
+}
+
+func (r *OctaviaFlavorReconciler) reconcileDelete(
+	ctx context.Context,
+	instance *octaviav1.OctaviaFlavor,
+	helper *helper.Helper,
+) (ctrl.Result, error) {
+	util.LogForObject(helper, "Reconciling flavor delete", instance)
+
+	if instance.Status.FlavorID != "" {
+		osclient, ctrlResult, err := getOctaviaClientForDelete(ctx, helper, instance.Namespace, &instance.Status.Conditions)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+
+		if osclient == nil {
+			util.LogForObject(helper, fmt.Sprintf("OctaviaAPI removed, flavor %s not deleted", instance.Status.FlavorID), instance)
+		} else {
+			ctrlResult, err = r.deleteFlavor(instance, osclient, instance.Status.FlavorID)
+			if err != nil {
+				return ctrlResult, err
+			} else if (ctrlResult != ctrl.Result{}) {
+				return ctrlResult, nil
+			}
+			util.LogForObject(helper, fmt.Sprintf("Deleted flavor %s", instance.Status.FlavorID), instance)
+		}
+		instance.Status.FlavorID = ""
+		instance.Status.FlavorProfileID = ""
+	}
+
+	controllerutil.RemoveFinalizer(instance, helper.GetFinalizer())
+	if err := r.Update(ctx, instance); err != nil && !k8s_errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	util.LogForObject(helper, "Reconciled flavor delete successfully", instance)
+	return ctrl.Result{}, nil
+}
+
+func (r *OctaviaFlavorReconciler) reconcileNormal(
+	ctx context.Context,
+	instance *octaviav1.OctaviaFlavor,
+	helper *helper.Helper,
+) (ctrl.Result, error) {
+	util.LogForObject(helper, "Reconciling flavor", instance)
+
+	if !controllerutil.ContainsFinalizer(instance, helper.GetFinalizer()) {
+		// If the object doesn't have our finalizer, add it.
+		controllerutil.AddFinalizer(instance, helper.GetFinalizer())
+		// Register the finalizer immediately to avoid orphaning the flavor on delete
+		err := r.Update(ctx, instance)
+
+		return ctrl.Result{}, err
+	}
+
+	//
+	// get the flavor profile the flavor uses
+	//
+	flavorProfile := &octaviav1.OctaviaFlavorProfile{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: instance.Spec.FlavorProfile, Namespace: instance.Namespace}, flavorProfile)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				octaviav1.FlavorProfileReadyCondition,
+				condition.RequestedReason,
+				condition.SeverityInfo,
+				octaviav1.FlavorProfileReadyWaitingMessage,
+				instance.Spec.FlavorProfile))
+			// the flavor profile watch triggers a reconcile when it gets created
+			return ctrl.Result{}, nil
+		}
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.FlavorProfileReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			octaviav1.FlavorProfileReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+	if !flavorProfile.IsReady() {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.FlavorProfileReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			octaviav1.FlavorProfileReadyWaitingMessage,
+			instance.Spec.FlavorProfile))
+		return ctrl.Result{}, nil
+	}
+	instance.Status.Conditions.MarkTrue(
+		octaviav1.FlavorProfileReadyCondition,
+		octaviav1.FlavorProfileReadyMessage,
+		flavorProfile.GetFlavorProfileName())
+
+	osclient, ctrlResult, err := getOctaviaClient(ctx, helper, instance.Namespace, &instance.Status.Conditions)
+	if err != nil {
+		return ctrlResult, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+
+	desired := octavia.Flavor{
+		ID:              instance.Status.FlavorID,
+		Name:            instance.GetFlavorName(),
+		Description:     instance.Spec.Description,
+		FlavorProfileID: flavorProfile.Status.FlavorProfileID,
+		Enabled:         instance.Spec.Enabled,
+	}
+
+	current, err := r.getFlavor(osclient, desired)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.FlavorReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			octaviav1.FlavorReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+
+	// The flavor profile of a flavor can not be updated in Octavia. Replace the
+	// flavor if it is not in use by any load balancer.
+	if current != nil && current.FlavorProfileID != desired.FlavorProfileID {
+		ctrlResult, err = r.deleteFlavor(instance, osclient, current.ID)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+		util.LogForObject(helper, fmt.Sprintf("Deleted flavor %s to change its flavor profile", current.ID), instance)
+		current = nil
+	}
+
+	if current == nil {
+		current, err = osclient.CreateFlavor(desired)
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				octaviav1.FlavorReadyCondition,
+				condition.CreationFailedReason,
+				condition.SeverityWarning,
+				octaviav1.FlavorReadyErrorMessage,
+				err.Error()))
+			return ctrl.Result{}, err
+		}
+		util.LogForObject(helper, fmt.Sprintf("Created flavor %s", current.ID), instance)
+	} else if current.Name != desired.Name ||
+		current.Description != desired.Description ||
+		current.Enabled != desired.Enabled {
+		// correct drift between the spec and the flavor in Octavia
+		desired.ID = current.ID
+		current, err = osclient.UpdateFlavor(desired)
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				octaviav1.FlavorReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				octaviav1.FlavorReadyErrorMessage,
+				err.Error()))
+			return ctrl.Result{}, err
+		}
+		util.LogForObject(helper, fmt.Sprintf("Updated flavor %s", current.ID), instance)
+	}
+
+	instance.Status.FlavorID = current.ID
+	instance.Status.FlavorProfileID = current.FlavorProfileID
+	instance.Status.Conditions.MarkTrue(
+		octaviav1.FlavorReadyCondition,
+		octaviav1.FlavorReadyMessage,
+		desired.Name)
+
+	util.LogForObject(helper, "Reconciled flavor successfully", instance)
+	return ctrl.Result{RequeueAfter: flavorDriftRequeue}, nil
+}
+
+// getFlavor - returns the flavor tracked in the status, or an existing one with
+// the same name to adopt it. Returns nil if there is none.
+func (r *OctaviaFlavorReconciler) getFlavor(
+	osclient *octavia.Client,
+	desired octavia.Flavor,
+) (*octavia.Flavor, error) {
+	if desired.ID != "" {
+		f, err := osclient.GetFlavor(desired.ID)
+		if err == nil {
+			return f, nil
+		}
+		if !octavia.IsNotFound(err) {
+			return nil, err
+		}
+		// the flavor got removed out of band, recreate it
+	}
+
+	flavors, err := osclient.ListFlavors(map[string]string{"name": desired.Name})
+	if err != nil {
+		return nil, err
+	}
+	if len(flavors) > 0 {
+		return &flavors[0], nil
+	}
+
+	return nil, nil
+}
+
+// deleteFlavor - deletes the flavor with id if no load balancer uses it
+func (r *OctaviaFlavorReconciler) deleteFlavor(
+	instance *octaviav1.OctaviaFlavor,
+	osclient *octavia.Client,
+	id string,
+) (ctrl.Result, error) {
+	lbs, err := osclient.CountLoadBalancers(map[string]string{"flavor_id": id})
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.FlavorReadyCondition,
+			condition.DeletionFailedReason,
+			condition.SeverityWarning,
+			octaviav1.FlavorReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+	if lbs > 0 {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.FlavorReadyCondition,
+			octaviav1.InUseReason,
+			condition.SeverityWarning,
+			octaviav1.FlavorInUseMessage,
+			lbs))
+		return ctrl.Result{RequeueAfter: octaviaClientRequeue}, nil
+	}
+
+	err = osclient.DeleteFlavor(id)
+	if err != nil && !octavia.IsNotFound(err) {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.FlavorReadyCondition,
+			condition.DeletionFailedReason,
+			condition.SeverityWarning,
+			octaviav1.FlavorReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	keystonev1 "github.com/openstack-k8s-operators/keystone-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia/octaviatest"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("OctaviaFlavorProfile and OctaviaFlavor controllers", func() {
+	var namespace string
+	var server *octaviatest.Server
+
+	// createReadyOctaviaAPI - a ready OctaviaAPI with the internal endpoint of the fake server
+	createReadyOctaviaAPI := func() {
+		createOctaviaAPIStub(namespace, map[string]string{"internal": server.URL},
+			condition.TrueCondition(condition.ExposeServiceReadyCondition, condition.ExposeServiceReadyMessage),
+			condition.TrueCondition(condition.DeploymentReadyCondition, condition.DeploymentReadyMessage))
+	}
+
+	createKeystoneAPIStub := func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "osp-secret",
+				Namespace: namespace,
+			},
+			StringData: map[string]string{
+				"AdminPassword": "12345678",
+			},
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+		keystoneAPI := &keystonev1.KeystoneAPI{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "keystone",
+				Namespace: namespace,
+			},
+			Spec: keystonev1.KeystoneAPISpec{
+				DatabaseInstance: "openstack",
+				ContainerImage:   "quay.io/tripleozedcentos9/openstack-keystone:current-tripleo",
+				Secret:           "osp-secret",
+				PasswordSelectors: keystonev1.PasswordSelector{
+					Admin: "AdminPassword",
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, keystoneAPI)).To(Succeed())
+		keystoneAPI.Status.APIEndpoints = map[string]string{
+			"public": server.IdentityURL(),
+		}
+		Expect(k8sClient.Status().Update(ctx, keystoneAPI)).To(Succeed())
+	}
+
+	createFlavorProfile := func() *octaviav1.OctaviaFlavorProfile {
+		fp := &octaviav1.OctaviaFlavorProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "amphora-single",
+				Namespace: namespace,
+			},
+			Spec: octaviav1.OctaviaFlavorProfileSpec{
+				ProviderName: "amphora",
+				FlavorData:   `{"loadbalancer_topology": "SINGLE"}`,
+			},
+		}
+		Expect(k8sClient.Create(ctx, fp)).To(Succeed())
+		return fp
+	}
+
+	createFlavor := func() *octaviav1.OctaviaFlavor {
+		f := &octaviav1.OctaviaFlavor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "single",
+				Namespace: namespace,
+			},
+			Spec: octaviav1.OctaviaFlavorSpec{
+				Description:   "single amphora",
+				FlavorProfile: "amphora-single",
+				Enabled:       true,
+			},
+		}
+		Expect(k8sClient.Create(ctx, f)).To(Succeed())
+		return f
+	}
+
+	BeforeEach(func() {
+		namespace = createNamespace()
+		server = octaviatest.NewServer()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("waits for the OctaviaAPI", func() {
+		fp := createFlavorProfile()
+		Eventually(getCondition(fp, &fp.Status.Conditions, octaviav1.OctaviaAPIReadyCondition), timeout, interval).
+			Should(HaveField("Reason", Equal(condition.RequestedReason)))
+
+		createKeystoneAPIStub()
+		createReadyOctaviaAPI()
+		Eventually(isReady(fp), timeout, interval).Should(BeTrue())
+		Expect(server.FlavorProfiles()).To(HaveLen(1))
+		Expect(server.FlavorProfiles()[0].ID).To(Equal(fp.Status.FlavorProfileID))
+	})
+
+	It("creates the flavor once its flavor profile is ready", func() {
+		f := createFlavor()
+		Eventually(getCondition(f, &f.Status.Conditions, octaviav1.FlavorProfileReadyCondition), timeout, interval).
+			Should(HaveField("Reason", Equal(condition.RequestedReason)))
+
+		createKeystoneAPIStub()
+		createReadyOctaviaAPI()
+		fp := createFlavorProfile()
+		Eventually(isReady(f), timeout, interval).Should(BeTrue())
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fp), fp)).To(Succeed())
+
+		Expect(server.Flavors()).To(Equal([]octaviatest.Flavor{{
+			ID:              f.Status.FlavorID,
+			Name:            "single",
+			Description:     "single amphora",
+			FlavorProfileID: fp.Status.FlavorProfileID,
+			Enabled:         true,
+		}}))
+	})
+
+	It("corrects drift of the flavor profile and flavor", func() {
+		createKeystoneAPIStub()
+		createReadyOctaviaAPI()
+		fp := createFlavorProfile()
+		f := createFlavor()
+		Eventually(isReady(f), timeout, interval).Should(BeTrue())
+
+		By("restoring the flavor data of the flavor profile")
+		changed := server.FlavorProfiles()[0]
+		changed.FlavorData = `{"loadbalancer_topology": "ACTIVE_STANDBY"}`
+		server.SetFlavorProfile(changed)
+		touch(fp)
+		Eventually(func() string {
+			return server.FlavorProfiles()[0].FlavorData
+		}, timeout, interval).Should(MatchJSON(`{"loadbalancer_topology": "SINGLE"}`))
+
+		By("enabling the flavor again")
+		disabled := server.Flavors()[0]
+		disabled.Enabled = false
+		server.SetFlavor(disabled)
+		touch(f)
+		Eventually(func() bool {
+			return server.Flavors()[0].Enabled
+		}, timeout, interval).Should(BeTrue())
+
+		By("recreating a flavor removed out of band")
+		removedID := f.Status.FlavorID
+		server.DeleteFlavor(removedID)
+		touch(f)
+		Eventually(func() string {
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(f), f)).To(Succeed())
+			return f.Status.FlavorID
+		}, timeout, interval).ShouldNot(Equal(removedID))
+		Expect(server.Flavors()).To(HaveLen(1))
+		Expect(server.Flavors()[0].ID).To(Equal(f.Status.FlavorID))
+	})
+
+	It("keeps the flavor while load balancers use it", func() {
+		createKeystoneAPIStub()
+		createReadyOctaviaAPI()
+		createFlavorProfile()
+		f := createFlavor()
+		Eventually(isReady(f), timeout, interval).Should(BeTrue())
+		lbID := server.SetLoadBalancer(octaviatest.LoadBalancer{Name: "lb", FlavorID: f.Status.FlavorID})
+
+		Expect(k8sClient.Delete(ctx, f)).To(Succeed())
+		Eventually(getCondition(f, &f.Status.Conditions, octaviav1.FlavorReadyCondition), timeout, interval).
+			Should(HaveField("Reason", BeEquivalentTo(octaviav1.InUseReason)))
+		Consistently(isDeleted(f), "2s", interval).Should(BeFalse())
+		Expect(server.Flavors()).To(HaveLen(1))
+
+		server.DeleteLoadBalancer(lbID)
+		Eventually(isDeleted(f), timeout, interval).Should(BeTrue())
+		Expect(server.Flavors()).To(BeEmpty())
+	})
+
+	It("keeps the flavor profile while flavors use it", func() {
+		createKeystoneAPIStub()
+		createReadyOctaviaAPI()
+		fp := createFlavorProfile()
+		f := createFlavor()
+		Eventually(isReady(f), timeout, interval).Should(BeTrue())
+
+		Expect(k8sClient.Delete(ctx, fp)).To(Succeed())
+		Eventually(getCondition(fp, &fp.Status.Conditions, octaviav1.FlavorProfileReadyCondition), timeout, interval).
+			Should(HaveField("Reason", BeEquivalentTo(octaviav1.InUseReason)))
+		Consistently(isDeleted(fp), "2s", interval).Should(BeFalse())
+		Expect(server.FlavorProfiles()).To(HaveLen(1))
+
+		Expect(k8sClient.Delete(ctx, f)).To(Succeed())
+		Eventually(isDeleted(f), timeout, interval).Should(BeTrue())
+		Eventually(isDeleted(fp), timeout, interval).Should(BeTrue())
+		Expect(server.FlavorProfiles()).To(BeEmpty())
+	})
+
+	It("keeps the flavor while the OctaviaAPI is not ready", func() {
+		createKeystoneAPIStub()
+		createReadyOctaviaAPI()
+		createFlavorProfile()
+		f := createFlavor()
+		Eventually(isReady(f), timeout, interval).Should(BeTrue())
+
+		setOctaviaAPIConditions(namespace, condition.FalseCondition(
+			condition.DeploymentReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			condition.DeploymentReadyRunningMessage))
+		Expect(k8sClient.Delete(ctx, f)).To(Succeed())
+		Eventually(getCondition(f, &f.Status.Conditions, octaviav1.OctaviaAPIReadyCondition), timeout, interval).
+			Should(HaveField("Reason", Equal(condition.RequestedReason)))
+		Consistently(isDeleted(f), "2s", interval).Should(BeFalse())
+		Expect(server.Flavors()).To(HaveLen(1))
+
+		setOctaviaAPIConditions(namespace,
+			condition.TrueCondition(condition.DeploymentReadyCondition, condition.DeploymentReadyMessage))
+		Eventually(isDeleted(f), timeout, interval).Should(BeTrue())
+		Expect(server.Flavors()).To(BeEmpty())
+	})
+
+	It("removes the finalizers without an OctaviaAPI", func() {
+		createKeystoneAPIStub()
+		createReadyOctaviaAPI()
+		fp := createFlavorProfile()
+		f := createFlavor()
+		Eventually(isReady(f), timeout, interval).Should(BeTrue())
+
+		octaviaAPI := &octaviav1.OctaviaAPI{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "octavia", Namespace: namespace}, octaviaAPI)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, octaviaAPI)).To(Succeed())
+		Eventually(isDeleted(octaviaAPI), timeout, interval).Should(BeTrue())
+
+		Expect(k8sClient.Delete(ctx, f)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, fp)).To(Succeed())
+		Eventually(isDeleted(f), timeout, interval).Should(BeTrue())
+		Eventually(isDeleted(fp), timeout, interval).Should(BeTrue())
+		// the resources are left in Octavia
+		Expect(server.Flavors()).To(HaveLen(1))
+	})
+})
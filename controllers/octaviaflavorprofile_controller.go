@@ -0,0 +1,319 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia"
+
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// flavorDriftRequeue - interval to check the Octavia resources for drift
+const flavorDriftRequeue = time.Minute * 5
+
+// OctaviaFlavorProfileReconciler reconciles a OctaviaFlavorProfile object
+type OctaviaFlavorProfileReconciler struct {
+	client.Client
+	Kclient kubernetes.Interface
+	Log     logr.Logger
+	Scheme  *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=octavia.openstack.org,resources=octaviaflavorprofiles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=octavia.openstack.org,resources=octaviaflavorprofiles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=octavia.openstack.org,resources=octaviaflavorprofiles/finalizers,verbs=update
+
+// Reconcile - creates, updates and deletes the flavor profile in the Octavia API
+func (r *OctaviaFlavorProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	_ = r.Log.WithValues("octaviaflavorprofile", req.NamespacedName)
+
+	instance := &octaviav1.OctaviaFlavorProfile{}
+	err := r.Client.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	//
+	// initialize status
+	//
+	if instance.Status.Conditions == nil {
+		instance.Status.Conditions = condition.Conditions{}
+
+		cl := condition.CreateList(
+			condition.UnknownCondition(octaviav1.OctaviaAPIReadyCondition, condition.InitReason, octaviav1.OctaviaAPIReadyInitMessage),
+			condition.UnknownCondition(octaviav1.FlavorProfileReadyCondition, condition.InitReason, octaviav1.FlavorProfileReadyInitMessage))
+
+		instance.Status.Conditions.Init(&cl)
+
+		// Register overall status immediately to have an early feedback e.g. in the cli
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	helper, err := helper.NewHelper(
+		instance,
+		r.Client,
+		r.Kclient,
+		r.Scheme,
+		r.Log,
+	)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always patch the instance status when exiting this function so we can persist any changes.
+	defer func() {
+		// update the overall status condition if service is ready
+		if instance.IsReady() {
+			instance.Status.Conditions.MarkTrue(condition.ReadyCondition, condition.ReadyMessage)
+		}
+
+		if err := helper.SetAfter(instance); err != nil {
+			util.LogErrorForObject(helper, err, "Set after and calc patch/diff", instance)
+		}
+
+		if changed := helper.GetChanges()["status"]; changed {
+			patch := client.MergeFrom(helper.GetBeforeObject())
+
+			if err := r.Status().Patch(ctx, instance, patch); err != nil && !k8s_errors.IsNotFound(err) {
+				util.LogErrorForObject(helper, err, "Update status", instance)
+			}
+		}
+	}()
+
+	// Handle flavor profile delete
+	if !instance.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, instance, helper)
+	}
+
+	// Handle non-deleted flavor profiles
+	return r.reconcileNormal(ctx, instance, helper)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OctaviaFlavorProfileReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&octaviav1.OctaviaFlavorProfile{}).
+		Complete(r)
+}
+
+func (r *OctaviaFlavorProfileReconciler) reconcileDelete(
+	ctx context.Context,
+	instance *octaviav1.OctaviaFlavorProfile,
+	helper *helper.Helper,
+) (ctrl.Result, error) {
+	util.LogForObject(helper, "Reconciling flavor profile delete", instance)
+
+	if instance.Status.FlavorProfileID != "" {
+		osclient, ctrlResult, err := getOctaviaClientForDelete(ctx, helper, instance.Namespace, &instance.Status.Conditions)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+
+		if osclient == nil {
+			util.LogForObject(helper, fmt.Sprintf("OctaviaAPI removed, flavor profile %s not deleted", instance.Status.FlavorProfileID), instance)
+		} else {
+			// do not delete the flavor profile as long as there are flavors using it
+			flavors, err := osclient.ListFlavors(map[string]string{
+				"flavor_profile_id": instance.Status.FlavorProfileID,
+			})
+			if err != nil {
+				instance.Status.Conditions.Set(condition.FalseCondition(
+					octaviav1.FlavorProfileReadyCondition,
+					condition.DeletionFailedReason,
+					condition.SeverityWarning,
+					octaviav1.FlavorProfileReadyErrorMessage,
+					err.Error()))
+				return ctrl.Result{}, err
+			}
+			if len(flavors) > 0 {
+				instance.Status.Conditions.Set(condition.FalseCondition(
+					octaviav1.FlavorProfileReadyCondition,
+					octaviav1.InUseReason,
+					condition.SeverityWarning,
+					octaviav1.FlavorProfileInUseMessage,
+					len(flavors)))
+				return ctrl.Result{RequeueAfter: octaviaClientRequeue}, nil
+			}
+
+			err = osclient.DeleteFlavorProfile(instance.Status.FlavorProfileID)
+			if err != nil && !octavia.IsNotFound(err) {
+				reason := condition.DeletionFailedReason
+				if octavia.IsConflict(err) {
+					reason = octaviav1.InUseReason
+				}
+				instance.Status.Conditions.Set(condition.FalseCondition(
+					octaviav1.FlavorProfileReadyCondition,
+					condition.Reason(reason),
+					condition.SeverityWarning,
+					octaviav1.FlavorProfileReadyErrorMessage,
+					err.Error()))
+				return ctrl.Result{}, err
+			}
+			util.LogForObject(helper, fmt.Sprintf("Deleted flavor profile %s", instance.Status.FlavorProfileID), instance)
+		}
+		instance.Status.FlavorProfileID = ""
+	}
+
+	controllerutil.RemoveFinalizer(instance, helper.GetFinalizer())
+	if err := r.Update(ctx, instance); err != nil && !k8s_errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	util.LogForObject(helper, "Reconciled flavor profile delete successfully", instance)
+	return ctrl.Result{}, nil
+}
+
+func (r *OctaviaFlavorProfileReconciler) reconcileNormal(
+	ctx context.Context,
+	instance *octaviav1.OctaviaFlavorProfile,
+	helper *helper.Helper,
+) (ctrl.Result, error) {
+	util.LogForObject(helper, "Reconciling flavor profile", instance)
+
+	if !controllerutil.ContainsFinalizer(instance, helper.GetFinalizer()) {
+		// If the object doesn't have our finalizer, add it.
+		controllerutil.AddFinalizer(instance, helper.GetFinalizer())
+		// Register the finalizer immediately to avoid orphaning the flavor profile on delete
+		err := r.Update(ctx, instance)
+
+		return ctrl.Result{}, err
+	}
+
+	if err := util.IsJSON(instance.Spec.FlavorData); err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.FlavorProfileReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityError,
+			octaviav1.FlavorProfileReadyErrorMessage,
+			fmt.Sprintf("flavorData is not valid JSON: %s", err.Error())))
+		// no need to requeue, a change of the spec will trigger a new reconcile
+		return ctrl.Result{}, nil
+	}
+
+	osclient, ctrlResult, err := getOctaviaClient(ctx, helper, instance.Namespace, &instance.Status.Conditions)
+	if err != nil {
+		return ctrlResult, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+
+	desired := octavia.FlavorProfile{
+		ID:           instance.Status.FlavorProfileID,
+		Name:         instance.GetFlavorProfileName(),
+		ProviderName: instance.Spec.ProviderName,
+		FlavorData:   instance.Spec.FlavorData,
+	}
+
+	current, err := r.getFlavorProfile(osclient, desired)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.FlavorProfileReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			octaviav1.FlavorProfileReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+
+	if current == nil {
+		current, err = osclient.CreateFlavorProfile(desired)
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				octaviav1.FlavorProfileReadyCondition,
+				condition.CreationFailedReason,
+				condition.SeverityWarning,
+				octaviav1.FlavorProfileReadyErrorMessage,
+				err.Error()))
+			return ctrl.Result{}, err
+		}
+		util.LogForObject(helper, fmt.Sprintf("Created flavor profile %s", current.ID), instance)
+	} else if current.Name != desired.Name ||
+		current.ProviderName != desired.ProviderName ||
+		!octavia.FlavorDataEqual(current.FlavorData, desired.FlavorData) {
+		// correct drift between the spec and the flavor profile in Octavia
+		desired.ID = current.ID
+		current, err = osclient.UpdateFlavorProfile(desired)
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				octaviav1.FlavorProfileReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				octaviav1.FlavorProfileReadyErrorMessage,
+				err.Error()))
+			return ctrl.Result{}, err
+		}
+		util.LogForObject(helper, fmt.Sprintf("Updated flavor profile %s", current.ID), instance)
+	}
+
+	instance.Status.FlavorProfileID = current.ID
+	instance.Status.Conditions.MarkTrue(
+		octaviav1.FlavorProfileReadyCondition,
+		octaviav1.FlavorProfileReadyMessage,
+		desired.Name)
+
+	util.LogForObject(helper, "Reconciled flavor profile successfully", instance)
+	return ctrl.Result{RequeueAfter: flavorDriftRequeue}, nil
+}
+
+// getFlavorProfile - returns the flavor profile tracked in the status, or an existing one with
+// the same name to adopt it. Returns nil if there is none.
+func (r *OctaviaFlavorProfileReconciler) getFlavorProfile(
+	osclient *octavia.Client,
+	desired octavia.FlavorProfile,
+) (*octavia.FlavorProfile, error) {
+	if desired.ID != "" {
+		fp, err := osclient.GetFlavorProfile(desired.ID)
+		if err == nil {
+			return fp, nil
+		}
+		if !octavia.IsNotFound(err) {
+			return nil, err
+		}
+		// the flavor profile got removed out of band, recreate it
+	}
+
+	fps, err := osclient.ListFlavorProfiles(map[string]string{"name": desired.Name})
+	if err != nil {
+		return nil, err
+	}
+	if len(fps) > 0 {
+		return &fps[0], nil
+	}
+
+	return nil, nil
+}
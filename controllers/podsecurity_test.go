@@ -0,0 +1,101 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("OctaviaAPI pod security", func() {
+	var namespace string
+	var instance *octaviav1.OctaviaAPI
+	labels := map[string]string{"service": octavia.ServiceName}
+
+	BeforeEach(func() {
+		// the PodSecurity admission rejects pods violating the restricted standard
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "octavia-restricted-",
+				Labels: map[string]string{
+					"pod-security.kubernetes.io/enforce": "restricted",
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+		namespace = ns.Name
+
+		instance = &octaviav1.OctaviaAPI{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "octavia",
+				Namespace: namespace,
+			},
+			Spec: octaviav1.OctaviaAPISpec{
+				DatabaseInstance: "openstack",
+				DatabaseUser:     "octavia",
+				ServiceUser:      "octavia",
+				ContainerImage:   "quay.io/tripleozedcentos9/openstack-octavia-api:current-tripleo",
+				Secret:           "osp-secret",
+				Replicas:         1,
+				EnabledProviders: []octaviav1.ProviderDriver{
+					octaviav1.ProviderDriverAmphora,
+					octaviav1.ProviderDriverOVN,
+				},
+			},
+		}
+	})
+
+	It("admits the API pod with the driver agent in a restricted namespace", func() {
+		depl := octavia.Deployment(instance, "hash", labels)
+		podSpec := depl.Spec.Template.Spec
+
+		Expect(*podSpec.SecurityContext.RunAsNonRoot).To(BeTrue())
+		Expect(*podSpec.SecurityContext.RunAsUser).To(Equal(octavia.OctaviaUID))
+		for _, c := range append(podSpec.InitContainers, podSpec.Containers...) {
+			Expect(*c.SecurityContext.ReadOnlyRootFilesystem).To(BeTrue(), c.Name)
+			Expect(c.SecurityContext.Capabilities.Drop).To(ContainElement(corev1.Capability("ALL")), c.Name)
+		}
+
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "octavia-api",
+				Namespace: namespace,
+			},
+			Spec: podSpec,
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+	})
+
+	It("admits the db-sync pod in a restricted namespace", func() {
+		job := octavia.DbSyncJob(instance, labels)
+
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "octavia-db-sync",
+				Namespace: namespace,
+			},
+			Spec: job.Spec.Template.Spec,
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+	})
+})
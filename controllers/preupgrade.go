@@ -0,0 +1,141 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// preUpgradeBackupRequeue - time to wait for the pre-upgrade backup job
+const preUpgradeBackupRequeue = time.Second * 10
+
+//
+// reconcilePreUpgradeBackup - dumps the DB before the db-sync job with dbSyncJobHash upgrades it to a
+// new container image. Returns a non empty result or an error while the db-sync must not run yet.
+// The upgrade is refused if the backup failed, unless the SkipPreUpgradeBackupAnnotation is set.
+//
+func (r *OctaviaAPIReconciler) reconcilePreUpgradeBackup(
+	ctx context.Context,
+	instance *octaviav1.OctaviaAPI,
+	h *helper.Helper,
+	serviceLabels map[string]string,
+	dbSyncJobHash string,
+) (ctrl.Result, error) {
+	if !instance.Spec.PreUpgradeBackup.Enabled {
+		removeCondition(&instance.Status.Conditions, octaviav1.PreUpgradeBackupReadyCondition)
+		return ctrl.Result{}, nil
+	}
+
+	// only a synced DB which gets synced again with a new image gets upgraded
+	dbSyncHash := instance.Status.Hash[octaviav1.DbSyncHash]
+	if dbSyncHash == "" || dbSyncHash == dbSyncJobHash ||
+		instance.Status.DBSyncImage == instance.Spec.ContainerImage {
+		return ctrl.Result{}, nil
+	}
+
+	backupStatus := instance.Status.PreUpgradeBackup
+	if backupStatus == nil || backupStatus.ContainerImage != instance.Spec.ContainerImage {
+		// the start time names the dump, it gets stored with a precision of seconds
+		backupStatus = &octaviav1.OctaviaPreUpgradeBackupStatus{
+			ContainerImage: instance.Spec.ContainerImage,
+			StartTime:      metav1.NewTime(time.Now().UTC().Truncate(time.Second)),
+		}
+		instance.Status.PreUpgradeBackup = backupStatus
+	}
+	if backupStatus.CompletionTime != nil {
+		return ctrl.Result{}, nil
+	}
+
+	backup := octavia.PreUpgradeBackup(instance, backupStatus.StartTime)
+	backupStatus.Location = octavia.DBBackupLocation(backup.Spec.Target, octavia.DBBackupFileName(backup))
+
+	failure := ""
+	if err := octavia.ValidateDBBackupTarget(backup.Spec.Target); err != nil {
+		failure = "persistentVolumeClaim is required"
+	} else {
+		job, err := ensureOneShotJob(ctx, h, instance, octavia.PreUpgradeBackupJob(instance, backupStatus.StartTime, serviceLabels))
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				octaviav1.PreUpgradeBackupReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				octaviav1.PreUpgradeBackupReadyErrorMessage,
+				err.Error()))
+			return ctrl.Result{}, err
+		}
+
+		var finished bool
+		finished, failure = getJobResult(job)
+		if !finished {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				octaviav1.PreUpgradeBackupReadyCondition,
+				condition.RequestedReason,
+				condition.SeverityInfo,
+				octaviav1.PreUpgradeBackupReadyRunningMessage))
+			return ctrl.Result{RequeueAfter: preUpgradeBackupRequeue}, nil
+		}
+		if failure != "" {
+			failure = fmt.Sprintf("job %s failed, %s", job.Name, failure)
+		} else {
+			completionTime := metav1.Now()
+			if job.Status.CompletionTime != nil {
+				completionTime = *job.Status.CompletionTime
+			}
+			backupStatus.CompletionTime = &completionTime
+			instance.Status.Conditions.MarkTrue(
+				octaviav1.PreUpgradeBackupReadyCondition,
+				octaviav1.PreUpgradeBackupReadyMessage,
+				backupStatus.Location)
+
+			if err := deleteFinishedJob(ctx, h, job, instance.Spec.PreserveJobs); err != nil {
+				return ctrl.Result{}, err
+			}
+			util.LogForObject(h, fmt.Sprintf("Pre-upgrade DB backup %s completed", backupStatus.Location), instance)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// the failed job is kept to check the logs, deleting it runs the backup again
+	if instance.GetAnnotations()[octaviav1.SkipPreUpgradeBackupAnnotation] == "true" {
+		util.LogForObject(h, fmt.Sprintf("Pre-upgrade DB backup failed, skipped: %s", failure), instance)
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			octaviav1.PreUpgradeBackupReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			octaviav1.PreUpgradeBackupReadySkippedMessage,
+			octaviav1.SkipPreUpgradeBackupAnnotation))
+		return ctrl.Result{}, nil
+	}
+	instance.Status.Conditions.Set(condition.FalseCondition(
+		octaviav1.PreUpgradeBackupReadyCondition,
+		condition.ErrorReason,
+		condition.SeverityWarning,
+		octaviav1.PreUpgradeBackupReadyErrorMessage,
+		failure))
+	return ctrl.Result{}, fmt.Errorf("pre-upgrade DB backup failed, refusing to upgrade the DB: %s", failure)
+}
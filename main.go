@@ -108,12 +108,49 @@ func main() {
 	}
 
 	if err = (&controllers.OctaviaAPIReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Kclient:  kclient,
+		Log:      ctrl.Log.WithName("controllers").WithName("OctaviaAPI"),
+		Recorder: mgr.GetEventRecorderFor("octaviaapi-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OctaviaAPI")
+		os.Exit(1)
+	}
+	if err = (&controllers.OctaviaFlavorProfileReconciler{
 		Client:  mgr.GetClient(),
 		Scheme:  mgr.GetScheme(),
 		Kclient: kclient,
-		Log:     ctrl.Log.WithName("controllers").WithName("OctaviaAPI"),
+		Log:     ctrl.Log.WithName("controllers").WithName("OctaviaFlavorProfile"),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "OctaviaAPI")
+		setupLog.Error(err, "unable to create controller", "controller", "OctaviaFlavorProfile")
+		os.Exit(1)
+	}
+	if err = (&controllers.OctaviaFlavorReconciler{
+		Client:  mgr.GetClient(),
+		Scheme:  mgr.GetScheme(),
+		Kclient: kclient,
+		Log:     ctrl.Log.WithName("controllers").WithName("OctaviaFlavor"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OctaviaFlavor")
+		os.Exit(1)
+	}
+	if err = (&controllers.OctaviaDBBackupReconciler{
+		Client:  mgr.GetClient(),
+		Scheme:  mgr.GetScheme(),
+		Kclient: kclient,
+		Log:     ctrl.Log.WithName("controllers").WithName("OctaviaDBBackup"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OctaviaDBBackup")
+		os.Exit(1)
+	}
+	if err = (&controllers.OctaviaDBRestoreReconciler{
+		Client:  mgr.GetClient(),
+		Scheme:  mgr.GetScheme(),
+		Kclient: kclient,
+		Log:     ctrl.Log.WithName("controllers").WithName("OctaviaDBRestore"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OctaviaDBRestore")
 		os.Exit(1)
 	}
 	//+kubebuilder:scaffold:builder
@@ -0,0 +1,230 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package exporter implements a Prometheus collector for the load balancer, listener and
+// amphora statistics of the Octavia API.
+package exporter
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "octavia"
+
+// OctaviaClient - the Octavia API calls used by the exporter, implemented by octavia.Client
+type OctaviaClient interface {
+	ListLoadBalancers(filters map[string]string) ([]octavia.LoadBalancer, error)
+	GetLoadBalancerStats(id string) (*octavia.Stats, error)
+	ListListeners(filters map[string]string) ([]octavia.Listener, error)
+	GetListenerStats(id string) (*octavia.Stats, error)
+	ListAmphorae(filters map[string]string) ([]octavia.Amphora, error)
+}
+
+var (
+	lbLabels       = []string{"id", "name", "project_id"}
+	listenerLabels = []string{"id", "name", "project_id", "loadbalancer_id", "protocol", "protocol_port"}
+
+	upDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "exporter", "up"),
+		"Whether the last poll of the Octavia API succeeded",
+		nil, nil)
+	lastPollDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "exporter", "last_poll_timestamp_seconds"),
+		"Time of the last poll of the Octavia API",
+		nil, nil)
+
+	lbStatusDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "loadbalancer", "status"),
+		"Provisioning and operating status of the load balancer, always 1",
+		append(lbLabels, "provider", "provisioning_status", "operating_status"), nil)
+	amphoraStatusDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "amphora", "status"),
+		"Status of the amphora, always 1",
+		[]string{"id", "loadbalancer_id", "compute_id", "role", "status"}, nil)
+)
+
+// statsDescs - descriptors of the load balancer or listener traffic statistics
+type statsDescs struct {
+	bytesIn           *prometheus.Desc
+	bytesOut          *prometheus.Desc
+	activeConnections *prometheus.Desc
+	totalConnections  *prometheus.Desc
+	requestErrors     *prometheus.Desc
+}
+
+func newStatsDescs(subsystem string, labels []string) statsDescs {
+	return statsDescs{
+		bytesIn: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "bytes_in_total"),
+			"Total bytes received", labels, nil),
+		bytesOut: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "bytes_out_total"),
+			"Total bytes sent", labels, nil),
+		activeConnections: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "active_connections"),
+			"Currently active connections", labels, nil),
+		totalConnections: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "connections_total"),
+			"Total handled connections", labels, nil),
+		requestErrors: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "request_errors_total"),
+			"Total failed requests", labels, nil),
+	}
+}
+
+func (d statsDescs) metrics(stats *octavia.Stats, labelValues ...string) []prometheus.Metric {
+	return []prometheus.Metric{
+		prometheus.MustNewConstMetric(d.bytesIn, prometheus.CounterValue, float64(stats.BytesIn), labelValues...),
+		prometheus.MustNewConstMetric(d.bytesOut, prometheus.CounterValue, float64(stats.BytesOut), labelValues...),
+		prometheus.MustNewConstMetric(d.activeConnections, prometheus.GaugeValue, float64(stats.ActiveConnections), labelValues...),
+		prometheus.MustNewConstMetric(d.totalConnections, prometheus.CounterValue, float64(stats.TotalConnections), labelValues...),
+		prometheus.MustNewConstMetric(d.requestErrors, prometheus.CounterValue, float64(stats.RequestErrors), labelValues...),
+	}
+}
+
+var (
+	lbStatsDescs       = newStatsDescs("loadbalancer", lbLabels)
+	listenerStatsDescs = newStatsDescs("listener", listenerLabels)
+)
+
+// Exporter - polls the statistics from the Octavia API and serves the result of the last poll
+type Exporter struct {
+	client OctaviaClient
+	log    logr.Logger
+
+	mu       sync.Mutex
+	metrics  []prometheus.Metric
+	up       bool
+	lastPoll time.Time
+}
+
+// NewExporter - returns an exporter polling using client
+func NewExporter(client OctaviaClient, log logr.Logger) *Exporter {
+	return &Exporter{
+		client: client,
+		log:    log,
+	}
+}
+
+// Describe - implements prometheus.Collector. The load balancers are only known after a poll,
+// so the exporter is an unchecked collector.
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {}
+
+// Collect - implements prometheus.Collector and sends the metrics of the last poll
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	up := 0.0
+	if e.up {
+		up = 1
+	}
+	ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, up)
+	if !e.lastPoll.IsZero() {
+		ch <- prometheus.MustNewConstMetric(lastPollDesc, prometheus.GaugeValue, float64(e.lastPoll.Unix()))
+	}
+	for _, m := range e.metrics {
+		ch <- m
+	}
+}
+
+// Run - polls the Octavia API every interval until ctx is done
+func (e *Exporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		e.Poll()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll - gets the current statistics from the Octavia API. If the load balancers or listeners
+// can not be listed, the metrics of the previous poll are kept and the exporter reports down.
+func (e *Exporter) Poll() {
+	metrics, err := e.poll()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lastPoll = time.Now()
+	e.up = err == nil
+	if err != nil {
+		e.log.Error(err, "Polling the Octavia API failed")
+		return
+	}
+	e.metrics = metrics
+}
+
+func (e *Exporter) poll() ([]prometheus.Metric, error) {
+	metrics := []prometheus.Metric{}
+
+	lbs, err := e.client.ListLoadBalancers(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, lb := range lbs {
+		metrics = append(metrics, prometheus.MustNewConstMetric(lbStatusDesc, prometheus.GaugeValue, 1,
+			lb.ID, lb.Name, lb.ProjectID, lb.Provider, lb.ProvisioningStatus, lb.OperatingStatus))
+
+		stats, err := e.client.GetLoadBalancerStats(lb.ID)
+		if err != nil {
+			// the load balancer might got deleted since it was listed
+			if octavia.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		metrics = append(metrics, lbStatsDescs.metrics(stats, lb.ID, lb.Name, lb.ProjectID)...)
+	}
+
+	listeners, err := e.client.ListListeners(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range listeners {
+		stats, err := e.client.GetListenerStats(l.ID)
+		if err != nil {
+			if octavia.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		lbID := ""
+		if len(l.LoadBalancers) > 0 {
+			lbID = l.LoadBalancers[0].ID
+		}
+		metrics = append(metrics, listenerStatsDescs.metrics(stats,
+			l.ID, l.Name, l.ProjectID, lbID, l.Protocol, strconv.Itoa(l.ProtocolPort))...)
+	}
+
+	// amphorae are only listed for the admin role, the other metrics are still useful without
+	amphorae, err := e.client.ListAmphorae(nil)
+	if err != nil {
+		e.log.Error(err, "Listing the amphorae failed")
+		return metrics, nil
+	}
+	for _, a := range amphorae {
+		metrics = append(metrics, prometheus.MustNewConstMetric(amphoraStatusDesc, prometheus.GaugeValue, 1,
+			a.ID, a.LoadBalancerID, a.ComputeID, a.Role, a.Status))
+	}
+
+	return metrics, nil
+}
@@ -0,0 +1,107 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type fakeClient struct {
+	listErr error
+}
+
+func (c *fakeClient) ListLoadBalancers(filters map[string]string) ([]octavia.LoadBalancer, error) {
+	if c.listErr != nil {
+		return nil, c.listErr
+	}
+	return []octavia.LoadBalancer{{
+		ID: "lb1", Name: "web", ProjectID: "p1", Provider: "amphora",
+		ProvisioningStatus: "ACTIVE", OperatingStatus: "ONLINE",
+	}}, nil
+}
+
+func (c *fakeClient) GetLoadBalancerStats(id string) (*octavia.Stats, error) {
+	return &octavia.Stats{BytesIn: 100, BytesOut: 200, ActiveConnections: 3, TotalConnections: 10, RequestErrors: 1}, nil
+}
+
+func (c *fakeClient) ListListeners(filters map[string]string) ([]octavia.Listener, error) {
+	l := octavia.Listener{ID: "l1", Name: "http", ProjectID: "p1", Protocol: "HTTP", ProtocolPort: 80}
+	l.LoadBalancers = append(l.LoadBalancers, struct {
+		ID string `json:"id"`
+	}{ID: "lb1"})
+	return []octavia.Listener{l}, nil
+}
+
+func (c *fakeClient) GetListenerStats(id string) (*octavia.Stats, error) {
+	return &octavia.Stats{BytesIn: 50}, nil
+}
+
+func (c *fakeClient) ListAmphorae(filters map[string]string) ([]octavia.Amphora, error) {
+	return nil, errors.New("forbidden")
+}
+
+func TestExporter(t *testing.T) {
+	client := &fakeClient{}
+	e := NewExporter(client, logr.Discard())
+	e.Poll()
+
+	expected := `
+# HELP octavia_exporter_up Whether the last poll of the Octavia API succeeded
+# TYPE octavia_exporter_up gauge
+octavia_exporter_up 1
+# HELP octavia_listener_bytes_in_total Total bytes received
+# TYPE octavia_listener_bytes_in_total counter
+octavia_listener_bytes_in_total{id="l1",loadbalancer_id="lb1",name="http",project_id="p1",protocol="HTTP",protocol_port="80"} 50
+# HELP octavia_loadbalancer_active_connections Currently active connections
+# TYPE octavia_loadbalancer_active_connections gauge
+octavia_loadbalancer_active_connections{id="lb1",name="web",project_id="p1"} 3
+# HELP octavia_loadbalancer_status Provisioning and operating status of the load balancer, always 1
+# TYPE octavia_loadbalancer_status gauge
+octavia_loadbalancer_status{id="lb1",name="web",operating_status="ONLINE",project_id="p1",provider="amphora",provisioning_status="ACTIVE"} 1
+`
+	err := testutil.CollectAndCompare(e, strings.NewReader(expected),
+		"octavia_exporter_up",
+		"octavia_listener_bytes_in_total",
+		"octavia_loadbalancer_active_connections",
+		"octavia_loadbalancer_status")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// a failed poll keeps the previous metrics and reports down
+	client.listErr = errors.New("unavailable")
+	e.Poll()
+	expected = `
+# HELP octavia_exporter_up Whether the last poll of the Octavia API succeeded
+# TYPE octavia_exporter_up gauge
+octavia_exporter_up 0
+# HELP octavia_loadbalancer_active_connections Currently active connections
+# TYPE octavia_loadbalancer_active_connections gauge
+octavia_loadbalancer_active_connections{id="lb1",name="web",project_id="p1"} 3
+`
+	err = testutil.CollectAndCompare(e, strings.NewReader(expected),
+		"octavia_exporter_up",
+		"octavia_loadbalancer_active_connections")
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,219 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics holds the octavia-operator specific Prometheus metrics. They get registered
+// with the controller-runtime registry and are served next to the default controller metrics.
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/prometheus/client_golang/prometheus"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	namespace = "octavia_operator"
+
+	// PhaseNormal - the reconcile of a non deleted OctaviaAPI
+	PhaseNormal = "normal"
+	// PhaseInit - DB creation, db-sync, keystone registration and service exposure
+	PhaseInit = "init"
+	// PhaseUpdate - minor update tasks
+	PhaseUpdate = "update"
+	// PhaseUpgrade - major version upgrade tasks
+	PhaseUpgrade = "upgrade"
+	// PhaseDeployment - the API deployment
+	PhaseDeployment = "deployment"
+	// PhaseDelete - the cleanup of a deleted OctaviaAPI
+	PhaseDelete = "delete"
+
+	// JobResultSucceeded - the job finished successfully
+	JobResultSucceeded = "succeeded"
+	// JobResultFailed - the job failed
+	JobResultFailed = "failed"
+)
+
+var (
+	reconcilePhaseDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "reconcile_phase_duration_seconds",
+			Help:      "Duration of the OctaviaAPI reconcile phases",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+		},
+		[]string{"phase"},
+	)
+
+	dbSyncJobDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "dbsync_job_duration_seconds",
+			Help:      "Duration of the finished db-sync jobs by result",
+			Buckets:   prometheus.ExponentialBuckets(5, 2, 10),
+		},
+		[]string{"result"},
+	)
+
+	timeToReady = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "api_time_to_ready_seconds",
+			Help:      "Time from the creation of the OctaviaAPI until it got ready the first time",
+		},
+		[]string{"namespace", "name"},
+	)
+
+	conditionStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "api_condition",
+			Help:      "Status of the OctaviaAPI conditions, 1 for the current status of the condition type",
+		},
+		[]string{"namespace", "name", "type", "status"},
+	)
+
+	keystoneRegistered = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "api_keystone_registered",
+			Help:      "Whether the keystone service and endpoints of the OctaviaAPI are registered",
+		},
+		[]string{"namespace", "name"},
+	)
+
+	replicas = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "api_replicas",
+			Help:      "Desired and ready replicas of the OctaviaAPI deployment",
+		},
+		[]string{"namespace", "name", "state"},
+	)
+)
+
+// conditionTypes - the condition types with an api_condition series per instance, to delete
+// the series of condition types which got removed from the instance
+var conditionTypes = struct {
+	sync.Mutex
+	byInstance map[string]map[condition.Type]bool
+}{byInstance: map[string]map[condition.Type]bool{}}
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		reconcilePhaseDuration,
+		dbSyncJobDuration,
+		timeToReady,
+		conditionStatus,
+		keystoneRegistered,
+		replicas,
+	)
+}
+
+// ObserveReconcilePhase - records the duration of a reconcile phase which started at start,
+// meant to be deferred at the beginning of the phase
+func ObserveReconcilePhase(phase string, start time.Time) {
+	reconcilePhaseDuration.WithLabelValues(phase).Observe(time.Since(start).Seconds())
+}
+
+// ObserveDBSyncJob - records the duration and result of a finished db-sync job, jobs which
+// did not finish yet are ignored
+func ObserveDBSyncJob(job *batchv1.Job) {
+	var result string
+	var end time.Time
+	switch {
+	case job.Status.Succeeded > 0 && job.Status.CompletionTime != nil:
+		result = JobResultSucceeded
+		end = job.Status.CompletionTime.Time
+	case job.Status.Failed > 0:
+		result = JobResultFailed
+		end = time.Now()
+		for _, c := range job.Status.Conditions {
+			if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+				end = c.LastTransitionTime.Time
+			}
+		}
+	default:
+		return
+	}
+	if job.Status.StartTime == nil {
+		return
+	}
+
+	dbSyncJobDuration.WithLabelValues(result).Observe(end.Sub(job.Status.StartTime.Time).Seconds())
+}
+
+// ObserveTimeToReady - records the time it took the instance to get ready
+func ObserveTimeToReady(instance *octaviav1.OctaviaAPI) {
+	timeToReady.WithLabelValues(instance.Namespace, instance.Name).Set(
+		time.Since(instance.CreationTimestamp.Time).Seconds())
+}
+
+// SetInstanceStatus - updates the condition, keystone registration and replica gauges of the instance
+func SetInstanceStatus(instance *octaviav1.OctaviaAPI) {
+	key := instance.Namespace + "/" + instance.Name
+	current := map[condition.Type]bool{}
+	for _, c := range instance.Status.Conditions {
+		current[c.Type] = true
+	}
+	conditionTypes.Lock()
+	for t := range conditionTypes.byInstance[key] {
+		if !current[t] {
+			conditionStatus.DeletePartialMatch(prometheus.Labels{
+				"namespace": instance.Namespace, "name": instance.Name, "type": string(t)})
+		}
+	}
+	conditionTypes.byInstance[key] = current
+	conditionTypes.Unlock()
+
+	for _, c := range instance.Status.Conditions {
+		for _, s := range []corev1.ConditionStatus{corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionUnknown} {
+			value := 0.0
+			if c.Status == s {
+				value = 1
+			}
+			conditionStatus.WithLabelValues(instance.Namespace, instance.Name, string(c.Type), string(s)).Set(value)
+		}
+	}
+
+	registered := 0.0
+	if instance.Status.Conditions.IsTrue(condition.KeystoneServiceReadyCondition) &&
+		instance.Status.Conditions.IsTrue(condition.KeystoneEndpointReadyCondition) {
+		registered = 1
+	}
+	keystoneRegistered.WithLabelValues(instance.Namespace, instance.Name).Set(registered)
+
+	replicas.WithLabelValues(instance.Namespace, instance.Name, "desired").Set(float64(instance.Status.DesiredReplicas))
+	replicas.WithLabelValues(instance.Namespace, instance.Name, "ready").Set(float64(instance.Status.ReadyCount))
+}
+
+// DeleteInstance - removes all per instance series, e.g. after the instance got deleted
+func DeleteInstance(namespace string, name string) {
+	conditionTypes.Lock()
+	delete(conditionTypes.byInstance, namespace+"/"+name)
+	conditionTypes.Unlock()
+
+	labels := prometheus.Labels{"namespace": namespace, "name": name}
+	timeToReady.DeletePartialMatch(labels)
+	conditionStatus.DeletePartialMatch(labels)
+	keystoneRegistered.DeletePartialMatch(labels)
+	replicas.DeletePartialMatch(labels)
+}
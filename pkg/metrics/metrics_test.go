@@ -0,0 +1,85 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestObserveDBSyncJob(t *testing.T) {
+	start := metav1.NewTime(time.Now().Add(-time.Minute))
+	end := metav1.NewTime(start.Add(30 * time.Second))
+
+	// still running, not recorded
+	ObserveDBSyncJob(&batchv1.Job{Status: batchv1.JobStatus{StartTime: &start, Active: 1}})
+	ObserveDBSyncJob(&batchv1.Job{Status: batchv1.JobStatus{StartTime: &start, CompletionTime: &end, Succeeded: 1}})
+
+	if count := testutil.CollectAndCount(dbSyncJobDuration); count != 1 {
+		t.Errorf("got %d db-sync job series, want 1", count)
+	}
+}
+
+func TestSetInstanceStatus(t *testing.T) {
+	instance := &octaviav1.OctaviaAPI{
+		ObjectMeta: metav1.ObjectMeta{Name: "octavia", Namespace: "openstack"},
+		Status: octaviav1.OctaviaAPIStatus{
+			ReadyCount:      1,
+			DesiredReplicas: 3,
+			Conditions: condition.CreateList(
+				condition.TrueCondition(condition.KeystoneServiceReadyCondition, "ready"),
+				condition.TrueCondition(condition.KeystoneEndpointReadyCondition, "ready"),
+				condition.FalseCondition(condition.DeploymentReadyCondition, condition.RequestedReason, condition.SeverityInfo, "rollout"),
+			),
+		},
+	}
+	SetInstanceStatus(instance)
+
+	if v := testutil.ToFloat64(conditionStatus.WithLabelValues("openstack", "octavia", string(condition.DeploymentReadyCondition), "False")); v != 1 {
+		t.Errorf("DeploymentReady False = %v, want 1", v)
+	}
+	if v := testutil.ToFloat64(conditionStatus.WithLabelValues("openstack", "octavia", string(condition.DeploymentReadyCondition), "True")); v != 0 {
+		t.Errorf("DeploymentReady True = %v, want 0", v)
+	}
+	if v := testutil.ToFloat64(keystoneRegistered.WithLabelValues("openstack", "octavia")); v != 1 {
+		t.Errorf("keystone registered = %v, want 1", v)
+	}
+	if v := testutil.ToFloat64(replicas.WithLabelValues("openstack", "octavia", "desired")); v != 3 {
+		t.Errorf("desired replicas = %v, want 3", v)
+	}
+
+	// removed condition types lose their series
+	instance.Status.Conditions = condition.CreateList(
+		condition.TrueCondition(condition.KeystoneServiceReadyCondition, "ready"),
+		condition.TrueCondition(condition.KeystoneEndpointReadyCondition, "ready"),
+	)
+	SetInstanceStatus(instance)
+	if count := testutil.CollectAndCount(conditionStatus); count != 6 {
+		t.Errorf("got %d condition series after removing a condition, want 6", count)
+	}
+
+	DeleteInstance("openstack", "octavia")
+	if count := testutil.CollectAndCount(replicas); count != 0 {
+		t.Errorf("got %d replica series after delete, want 0", count)
+	}
+}
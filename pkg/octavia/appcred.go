@@ -0,0 +1,205 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"fmt"
+	"time"
+
+	gophercloud "github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/applicationcredentials"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ApplicationCredentialIDKey - key of the application credential ID in the secret
+	ApplicationCredentialIDKey = "ApplicationCredentialID"
+	// ApplicationCredentialSecretKey - key of the application credential secret in the secret
+	ApplicationCredentialSecretKey = "ApplicationCredentialSecret"
+
+	// applicationCredentialCreatedAnnotation - creation time of the current application credential
+	applicationCredentialCreatedAnnotation = "octavia.openstack.org/application-credential-created"
+	// applicationCredentialPreviousAnnotation - ID of the previous application credential, which
+	// stays valid until the grace period after the rotation passed
+	applicationCredentialPreviousAnnotation = "octavia.openstack.org/application-credential-previous"
+)
+
+// ApplicationCredentialSecretName - name of the secret holding the application credential
+func ApplicationCredentialSecretName(instance *octaviav1.OctaviaAPI) string {
+	return instance.Name + "-application-credential"
+}
+
+// ApplicationCredentialState - the application credentials of the secret
+type ApplicationCredentialState struct {
+	ID         string
+	CreatedAt  time.Time
+	PreviousID string
+}
+
+// GetApplicationCredentialState - returns the application credentials recorded in the secret
+func GetApplicationCredentialState(secret *corev1.Secret) ApplicationCredentialState {
+	state := ApplicationCredentialState{
+		ID:         string(secret.Data[ApplicationCredentialIDKey]),
+		PreviousID: secret.Annotations[applicationCredentialPreviousAnnotation],
+	}
+	if created, err := time.Parse(time.RFC3339, secret.Annotations[applicationCredentialCreatedAnnotation]); err == nil {
+		state.CreatedAt = created
+	}
+	return state
+}
+
+// ApplicationCredentialSecret - the secret holding the application credential id and secret. The
+// previous credential gets recorded to remove it once the grace period passed.
+func ApplicationCredentialSecret(
+	instance *octaviav1.OctaviaAPI,
+	labels map[string]string,
+	id string,
+	secret string,
+	createdAt time.Time,
+	previousID string,
+) *corev1.Secret {
+	annotations := map[string]string{
+		applicationCredentialCreatedAnnotation: createdAt.UTC().Format(time.RFC3339),
+	}
+	if previousID != "" {
+		annotations[applicationCredentialPreviousAnnotation] = previousID
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ApplicationCredentialSecretName(instance),
+			Namespace:   instance.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			ApplicationCredentialIDKey:     []byte(id),
+			ApplicationCredentialSecretKey: []byte(secret),
+		},
+	}
+}
+
+// ApplicationCredentialRotation - returns if a new application credential has to be created, if
+// the previous one can be removed, and the time until the next rotation step is due
+func ApplicationCredentialRotation(
+	spec octaviav1.OctaviaApplicationCredential,
+	state ApplicationCredentialState,
+	now time.Time,
+) (rotate bool, removePrevious bool, next time.Duration) {
+	if state.ID == "" || state.CreatedAt.IsZero() {
+		return true, false, 0
+	}
+
+	rotateAt := state.CreatedAt.Add(spec.RotationPeriod.Duration)
+	if !now.Before(rotateAt) {
+		return true, false, 0
+	}
+	next = rotateAt.Sub(now)
+
+	if state.PreviousID != "" {
+		removeAt := state.CreatedAt.Add(spec.GracePeriod.Duration)
+		if !now.Before(removeAt) {
+			removePrevious = true
+		} else if removeAt.Sub(now) < next {
+			next = removeAt.Sub(now)
+		}
+	}
+
+	return false, removePrevious, next
+}
+
+// IdentityClient - keystone client authenticated as the service user, which manages the
+// application credentials of the user
+type IdentityClient struct {
+	serviceClient *gophercloud.ServiceClient
+	userID        string
+}
+
+// NewIdentityClient - authenticates as user of the service project at authURL
+func NewIdentityClient(authURL string, user string, password string) (*IdentityClient, error) {
+	providerClient, err := openstack.AuthenticatedClient(gophercloud.AuthOptions{
+		IdentityEndpoint: authURL,
+		Username:         user,
+		Password:         password,
+		DomainName:       "Default",
+		Scope: &gophercloud.AuthScope{
+			ProjectName: "service",
+			DomainName:  "Default",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	authResult, ok := providerClient.GetAuthResult().(tokens.CreateResult)
+	if !ok {
+		return nil, fmt.Errorf("unexpected authentication result of user %s", user)
+	}
+	tokenUser, err := authResult.ExtractUser()
+	if err != nil {
+		return nil, err
+	}
+
+	serviceClient, err := openstack.NewIdentityV3(providerClient, gophercloud.EndpointOpts{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &IdentityClient{
+		serviceClient: serviceClient,
+		userID:        tokenUser.ID,
+	}, nil
+}
+
+// CreateApplicationCredential - creates a restricted application credential for the user, limited
+// to the roles if set, which expires at expiresAt. Returns the id and the secret.
+func (c *IdentityClient) CreateApplicationCredential(
+	name string,
+	roles []string,
+	expiresAt time.Time,
+) (string, string, error) {
+	opts := applicationcredentials.CreateOpts{
+		Name:         name,
+		Description:  "Managed by the octavia-operator",
+		Unrestricted: false,
+		ExpiresAt:    &expiresAt,
+	}
+	for _, role := range roles {
+		opts.Roles = append(opts.Roles, applicationcredentials.Role{Name: role})
+	}
+
+	appCred, err := applicationcredentials.Create(c.serviceClient, c.userID, opts).Extract()
+	if err != nil {
+		return "", "", err
+	}
+	return appCred.ID, appCred.Secret, nil
+}
+
+// DeleteApplicationCredential - deletes the application credential with id, ignoring if it
+// does not exist anymore
+func (c *IdentityClient) DeleteApplicationCredential(id string) error {
+	err := applicationcredentials.Delete(c.serviceClient, c.userID, id).ExtractErr()
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"testing"
+	"time"
+
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia/octaviatest"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplicationCredentialState(t *testing.T) {
+	if state := GetApplicationCredentialState(&corev1.Secret{}); state != (ApplicationCredentialState{}) {
+		t.Errorf("expected an empty state for a missing secret, got %+v", state)
+	}
+
+	created := time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC)
+	secret := ApplicationCredentialSecret(testInstance(), nil, "new-id", "new-secret", created, "old-id")
+	if secret.Name != "octavia-application-credential" {
+		t.Errorf("unexpected secret name %s", secret.Name)
+	}
+
+	expected := ApplicationCredentialState{ID: "new-id", CreatedAt: created, PreviousID: "old-id"}
+	if state := GetApplicationCredentialState(secret); state != expected {
+		t.Errorf("expected %+v, got %+v", expected, state)
+	}
+}
+
+func TestApplicationCredentialRotation(t *testing.T) {
+	spec := octaviav1.OctaviaApplicationCredential{
+		Enabled:        true,
+		RotationPeriod: metav1.Duration{Duration: 720 * time.Hour},
+		GracePeriod:    metav1.Duration{Duration: 24 * time.Hour},
+	}
+	created := time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		state          ApplicationCredentialState
+		now            time.Time
+		rotate         bool
+		removePrevious bool
+		next           time.Duration
+	}{
+		{
+			name:   "no credential",
+			state:  ApplicationCredentialState{},
+			now:    created,
+			rotate: true,
+		},
+		{
+			name:  "within the rotation period",
+			state: ApplicationCredentialState{ID: "id", CreatedAt: created},
+			now:   created.Add(20 * 24 * time.Hour),
+			next:  10 * 24 * time.Hour,
+		},
+		{
+			name:   "rotation period passed",
+			state:  ApplicationCredentialState{ID: "id", CreatedAt: created},
+			now:    created.Add(720 * time.Hour),
+			rotate: true,
+		},
+		{
+			name:  "previous credential within the grace period",
+			state: ApplicationCredentialState{ID: "id", CreatedAt: created, PreviousID: "old"},
+			now:   created.Add(time.Hour),
+			next:  23 * time.Hour,
+		},
+		{
+			name:           "grace period passed",
+			state:          ApplicationCredentialState{ID: "id", CreatedAt: created, PreviousID: "old"},
+			now:            created.Add(25 * time.Hour),
+			removePrevious: true,
+			next:           695 * time.Hour,
+		},
+	}
+
+	for _, tc := range tests {
+		rotate, removePrevious, next := ApplicationCredentialRotation(spec, tc.state, tc.now)
+		if rotate != tc.rotate || removePrevious != tc.removePrevious || next != tc.next {
+			t.Errorf("%s: expected rotate %v, removePrevious %v, next %s, got %v, %v, %s",
+				tc.name, tc.rotate, tc.removePrevious, tc.next, rotate, removePrevious, next)
+		}
+	}
+}
+
+func TestIdentityClient(t *testing.T) {
+	server := octaviatest.NewServer()
+	defer server.Close()
+
+	c, err := NewIdentityClient(server.IdentityURL(), "octavia", "12345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, secret, err := c.CreateApplicationCredential("octavia-20221101120000", []string{"service"}, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if appCreds := server.ApplicationCredentials(); len(appCreds) != 1 || appCreds[0].ID != id || appCreds[0].Secret != secret {
+		t.Errorf("expected the application credential %s, got %v", id, appCreds)
+	}
+
+	if err := c.DeleteApplicationCredential(id); err != nil {
+		t.Fatal(err)
+	}
+	if appCreds := server.ApplicationCredentials(); len(appCreds) != 0 {
+		t.Errorf("expected no application credential, got %v", appCreds)
+	}
+	// already removed
+	if err := c.DeleteApplicationCredential(id); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
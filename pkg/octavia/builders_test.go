@@ -0,0 +1,285 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// update - rewrite the golden files with the current output of the builders, run with
+// go test ./pkg/octavia/... -update and review the diff of pkg/octavia/testdata
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// builderTestCases - matrix of specs the builders get rendered for, the name is the
+// prefix of the golden files
+var builderTestCases = []struct {
+	name   string
+	mutate func(instance *octaviav1.OctaviaAPI)
+}{
+	{
+		name:   "default",
+		mutate: func(instance *octaviav1.OctaviaAPI) {},
+	},
+	{
+		name: "debug",
+		mutate: func(instance *octaviav1.OctaviaAPI) {
+			instance.Spec.Debug.DBSync = true
+			instance.Spec.Debug.Service = true
+		},
+	},
+	{
+		name: "nodeselector",
+		mutate: func(instance *octaviav1.OctaviaAPI) {
+			instance.Spec.NodeSelector = map[string]string{
+				"node-role.kubernetes.io/worker": "",
+			}
+		},
+	},
+	{
+		name: "scheduling",
+		mutate: func(instance *octaviav1.OctaviaAPI) {
+			instance.Spec.Tolerations = []corev1.Toleration{
+				{
+					Key:      "node-role.kubernetes.io/infra",
+					Operator: corev1.TolerationOpExists,
+					Effect:   corev1.TaintEffectNoSchedule,
+				},
+			}
+			instance.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{
+				{
+					MaxSkew:           1,
+					TopologyKey:       corev1.LabelTopologyZone,
+					WhenUnsatisfiable: corev1.ScheduleAnyway,
+				},
+			}
+			instance.Spec.PriorityClassName = "openstack-control-plane"
+			instance.Spec.Affinity = &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{
+							{
+								MatchExpressions: []corev1.NodeSelectorRequirement{
+									{
+										Key:      "node-role.kubernetes.io/infra",
+										Operator: corev1.NodeSelectorOpExists,
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+		},
+	},
+	{
+		name: "endpoints",
+		mutate: func(instance *octaviav1.OctaviaAPI) {
+			instance.Spec.Endpoints = octaviav1.OctaviaAPIEndpoints{
+				Internal: octaviav1.OctaviaAPIEndpoint{
+					Port:      9877,
+					TLSSecret: "octavia-internal-tls",
+				},
+				Public: octaviav1.OctaviaAPIEndpoint{
+					Port:      13876,
+					TLSSecret: "octavia-public-tls",
+				},
+			}
+		},
+	},
+	{
+		name: "resources",
+		mutate: func(instance *octaviav1.OctaviaAPI) {
+			instance.Spec.Resources = corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("500m"),
+					corev1.ResourceMemory: resource.MustParse("512Mi"),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			}
+		},
+	},
+	{
+		name: "replicas",
+		mutate: func(instance *octaviav1.OctaviaAPI) {
+			instance.Spec.Replicas = 3
+		},
+	},
+	{
+		name: "maintenance",
+		mutate: func(instance *octaviav1.OctaviaAPI) {
+			instance.Spec.Replicas = 3
+			instance.Spec.Maintenance = true
+		},
+	},
+	{
+		name: "ovn",
+		mutate: func(instance *octaviav1.OctaviaAPI) {
+			instance.Spec.EnabledProviders = []octaviav1.ProviderDriver{
+				octaviav1.ProviderDriverAmphora,
+				octaviav1.ProviderDriverOVN,
+			}
+		},
+	},
+	{
+		name: "appcred",
+		mutate: func(instance *octaviav1.OctaviaAPI) {
+			instance.Spec.ApplicationCredential.Enabled = true
+		},
+	},
+	{
+		name: "dbsynclimits",
+		mutate: func(instance *octaviav1.OctaviaAPI) {
+			backoffLimit := int32(2)
+			activeDeadlineSeconds := int64(600)
+			ttlSecondsAfterFinished := int32(3600)
+			instance.Spec.DBSyncJob = octaviav1.OctaviaDBSyncJob{
+				BackoffLimit:            &backoffLimit,
+				ActiveDeadlineSeconds:   &activeDeadlineSeconds,
+				TTLSecondsAfterFinished: &ttlSecondsAfterFinished,
+			}
+		},
+	},
+}
+
+// dbSyncBackoffLimit - the default of spec.dbSyncJob.backoffLimit
+var dbSyncBackoffLimit int32 = 6
+
+// testInstance - OctaviaAPI with the defaults the CRD would set
+func testInstance() *octaviav1.OctaviaAPI {
+	return &octaviav1.OctaviaAPI{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "octavia",
+			Namespace: "openstack",
+		},
+		Spec: octaviav1.OctaviaAPISpec{
+			DatabaseInstance: "openstack",
+			DatabaseUser:     "octavia",
+			ServiceUser:      "octavia",
+			ContainerImage:   "quay.io/tripleozedcentos9/openstack-octavia-api:current-tripleo",
+			Replicas:         1,
+			Secret:           "osp-secret",
+			PasswordSelectors: octaviav1.PasswordSelector{
+				Database: "OctaviaDatabasePassword",
+				Service:  "OctaviaPassword",
+			},
+			EnabledProviders: []octaviav1.ProviderDriver{
+				octaviav1.ProviderDriverAmphora,
+			},
+			APIPort: OctaviaDefaultPort,
+			DBPurge: octaviav1.OctaviaDBPurge{
+				Schedule: "1 0 * * *",
+				Age:      30,
+			},
+			DBSyncJob: octaviav1.OctaviaDBSyncJob{
+				BackoffLimit: &dbSyncBackoffLimit,
+			},
+		},
+		Status: octaviav1.OctaviaAPIStatus{
+			DatabaseHostname: "openstack",
+		},
+	}
+}
+
+// assertGolden - compares the YAML representation of obj with testdata/<name>.yaml
+func assertGolden(t *testing.T, name string, obj interface{}) {
+	t.Helper()
+
+	actual, err := yaml.Marshal(obj)
+	if err != nil {
+		t.Fatalf("marshal %s: %v", name, err)
+	}
+
+	golden := filepath.Join("testdata", name+".yaml")
+	if *update {
+		if err := os.WriteFile(golden, actual, 0644); err != nil {
+			t.Fatalf("update %s: %v", golden, err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("read %s, run with -update to create it: %v", golden, err)
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("%s differs from the golden file, run with -update and review the diff\n--- expected\n%s\n--- actual\n%s",
+			golden, expected, actual)
+	}
+}
+
+func TestBuilders(t *testing.T) {
+	labels := map[string]string{"service": ServiceName}
+
+	for _, tc := range builderTestCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			instance := testInstance()
+			tc.mutate(instance)
+
+			assertGolden(t, tc.name+"_deployment", Deployment(instance, "confighash", labels))
+			assertGolden(t, tc.name+"_dbsync_job", DbSyncJob(instance, labels))
+		})
+	}
+}
+
+func TestInitContainer(t *testing.T) {
+	instance := testInstance()
+
+	assertGolden(t, "init_container", initContainer(APIDetails{
+		ContainerImage:       instance.Spec.ContainerImage,
+		DatabaseHost:         instance.Status.DatabaseHostname,
+		DatabaseUser:         instance.Spec.DatabaseUser,
+		DatabaseName:         DatabaseName,
+		OSPSecret:            instance.Spec.Secret,
+		DBPasswordSelector:   instance.Spec.PasswordSelectors.Database,
+		UserPasswordSelector: instance.Spec.PasswordSelectors.Service,
+		VolumeMounts:         getInitVolumeMounts(),
+	}))
+}
+
+func TestGetVolumes(t *testing.T) {
+	assertGolden(t, "volumes", getVolumes("octavia"))
+}
+
+func TestExporter(t *testing.T) {
+	instance := testInstance()
+	instance.Spec.Exporter = octaviav1.OctaviaExporter{
+		Enabled:        true,
+		ContainerImage: "quay.io/openstack-k8s-operators/octavia-operator:latest",
+		Port:           9120,
+		PollInterval:   metav1.Duration{Duration: time.Minute},
+	}
+	labels := ExporterLabels()
+
+	assertGolden(t, "exporter_deployment", ExporterDeployment(instance, labels,
+		"http://keystone-public-openstack.apps-crc.testing", "http://octavia-internal.openstack.svc:9876"))
+	assertGolden(t, "exporter_service", ExporterService(instance, labels))
+	assertGolden(t, "exporter_servicemonitor", ExporterServiceMonitor(instance, labels).Object)
+}
@@ -0,0 +1,398 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+
+	gophercloud "github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/pagination"
+)
+
+// updateOpts - the Octavia API returns 200 on updates, gophercloud only expects 201 and 202 by default
+var updateOpts = &gophercloud.RequestOpts{OkCodes: []int{200}}
+
+// Client - minimal client for the Octavia v2 API resources managed by the operator
+type Client struct {
+	serviceClient *gophercloud.ServiceClient
+}
+
+// FlavorProfile - Octavia flavor profile
+type FlavorProfile struct {
+	ID           string `json:"id,omitempty"`
+	Name         string `json:"name"`
+	ProviderName string `json:"provider_name"`
+	FlavorData   string `json:"flavor_data"`
+}
+
+// Flavor - Octavia flavor
+type Flavor struct {
+	ID              string `json:"id,omitempty"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	FlavorProfileID string `json:"flavor_profile_id,omitempty"`
+	Enabled         bool   `json:"enabled"`
+}
+
+// LoadBalancer - Octavia load balancer
+type LoadBalancer struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	ProjectID          string `json:"project_id"`
+	Provider           string `json:"provider"`
+	ProvisioningStatus string `json:"provisioning_status"`
+	OperatingStatus    string `json:"operating_status"`
+}
+
+// Listener - Octavia listener
+type Listener struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	ProjectID     string `json:"project_id"`
+	Protocol      string `json:"protocol"`
+	ProtocolPort  int    `json:"protocol_port"`
+	LoadBalancers []struct {
+		ID string `json:"id"`
+	} `json:"loadbalancers"`
+}
+
+// Amphora - Octavia amphora
+type Amphora struct {
+	ID             string `json:"id"`
+	LoadBalancerID string `json:"loadbalancer_id"`
+	ComputeID      string `json:"compute_id"`
+	Status         string `json:"status"`
+	Role           string `json:"role"`
+}
+
+// Stats - traffic statistics of a load balancer or listener
+type Stats struct {
+	BytesIn           int64 `json:"bytes_in"`
+	BytesOut          int64 `json:"bytes_out"`
+	ActiveConnections int64 `json:"active_connections"`
+	TotalConnections  int64 `json:"total_connections"`
+	RequestErrors     int64 `json:"request_errors"`
+}
+
+// NewClient - returns a client for the Octavia API at endpointURL which
+// authenticates using the passed, already authenticated, provider client
+func NewClient(
+	providerClient *gophercloud.ProviderClient,
+	endpointURL string,
+) *Client {
+	endpointURL = strings.TrimSuffix(endpointURL, "/") + "/"
+
+	return &Client{
+		serviceClient: &gophercloud.ServiceClient{
+			ProviderClient: providerClient,
+			Endpoint:       endpointURL,
+			ResourceBase:   endpointURL + "v2/",
+			Type:           "load-balancer",
+		},
+	}
+}
+
+// IsNotFound - returns true if the error returned by the Octavia API is a 404
+func IsNotFound(err error) bool {
+	_, ok := err.(gophercloud.ErrDefault404)
+	return ok
+}
+
+// IsConflict - returns true if the error returned by the Octavia API is a 409
+func IsConflict(err error) bool {
+	_, ok := err.(gophercloud.ErrDefault409)
+	return ok
+}
+
+// FlavorDataEqual - returns true if both JSON documents hold the same flavor data
+func FlavorDataEqual(a string, b string) bool {
+	var da, db interface{}
+	if err := json.Unmarshal([]byte(a), &da); err != nil {
+		return a == b
+	}
+	if err := json.Unmarshal([]byte(b), &db); err != nil {
+		return a == b
+	}
+	return reflect.DeepEqual(da, db)
+}
+
+// listPage - a page of an Octavia list response, which links the next page in <resource>_links
+type listPage struct {
+	pagination.LinkedPageBase
+	resource string
+}
+
+// NextPageURL - returns the next link of the page, or an empty string on the last page
+func (p listPage) NextPageURL() (string, error) {
+	var links []gophercloud.Link
+	if err := p.ExtractIntoSlicePtr(&links, p.resource+"_links"); err != nil {
+		return "", err
+	}
+	return gophercloud.ExtractNextURL(links)
+}
+
+// IsEmpty - returns true if the page holds no items
+func (p listPage) IsEmpty() (bool, error) {
+	var items []json.RawMessage
+	if err := p.ExtractIntoSlicePtr(&items, p.resource); err != nil {
+		return false, err
+	}
+	return len(items) == 0, nil
+}
+
+// list - gets the resources matching the filters, following the next links of the pages, and
+// appends them to the slice pointed to by items
+func (c *Client) list(resourceURL string, resource string, filters map[string]string, items interface{}) error {
+	pager := pagination.NewPager(c.serviceClient, resourceURL+listQuery(filters), func(r pagination.PageResult) pagination.Page {
+		return listPage{LinkedPageBase: pagination.LinkedPageBase{PageResult: r}, resource: resource}
+	})
+
+	all := reflect.ValueOf(items).Elem()
+	return pager.EachPage(func(page pagination.Page) (bool, error) {
+		pageItems := reflect.New(all.Type())
+		if err := page.(listPage).ExtractIntoSlicePtr(pageItems.Interface(), resource); err != nil {
+			return false, err
+		}
+		all.Set(reflect.AppendSlice(all, pageItems.Elem()))
+		return true, nil
+	})
+}
+
+func listQuery(filters map[string]string) string {
+	if len(filters) == 0 {
+		return ""
+	}
+	q := url.Values{}
+	for k, v := range filters {
+		q.Set(k, v)
+	}
+	return "?" + q.Encode()
+}
+
+//
+// Flavor profiles
+//
+
+// GetFlavorProfile - get flavor profile with id
+func (c *Client) GetFlavorProfile(id string) (*FlavorProfile, error) {
+	res := struct {
+		FlavorProfile FlavorProfile `json:"flavorprofile"`
+	}{}
+	_, err := c.serviceClient.Get(c.serviceClient.ServiceURL("lbaas", "flavorprofiles", id), &res, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &res.FlavorProfile, nil
+}
+
+// ListFlavorProfiles - list flavor profiles matching the filters
+func (c *Client) ListFlavorProfiles(filters map[string]string) ([]FlavorProfile, error) {
+	flavorProfiles := []FlavorProfile{}
+	if err := c.list(c.serviceClient.ServiceURL("lbaas", "flavorprofiles"), "flavorprofiles", filters, &flavorProfiles); err != nil {
+		return nil, err
+	}
+	return flavorProfiles, nil
+}
+
+// CreateFlavorProfile - create a flavor profile
+func (c *Client) CreateFlavorProfile(fp FlavorProfile) (*FlavorProfile, error) {
+	req := struct {
+		FlavorProfile FlavorProfile `json:"flavorprofile"`
+	}{FlavorProfile: fp}
+	req.FlavorProfile.ID = ""
+
+	res := struct {
+		FlavorProfile FlavorProfile `json:"flavorprofile"`
+	}{}
+	_, err := c.serviceClient.Post(c.serviceClient.ServiceURL("lbaas", "flavorprofiles"), &req, &res, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &res.FlavorProfile, nil
+}
+
+// UpdateFlavorProfile - update name, provider and flavor data of the flavor profile fp.ID
+func (c *Client) UpdateFlavorProfile(fp FlavorProfile) (*FlavorProfile, error) {
+	id := fp.ID
+	req := struct {
+		FlavorProfile FlavorProfile `json:"flavorprofile"`
+	}{FlavorProfile: fp}
+	req.FlavorProfile.ID = ""
+
+	res := struct {
+		FlavorProfile FlavorProfile `json:"flavorprofile"`
+	}{}
+	_, err := c.serviceClient.Put(c.serviceClient.ServiceURL("lbaas", "flavorprofiles", id), &req, &res, updateOpts)
+	if err != nil {
+		return nil, err
+	}
+	return &res.FlavorProfile, nil
+}
+
+// DeleteFlavorProfile - delete flavor profile with id
+func (c *Client) DeleteFlavorProfile(id string) error {
+	_, err := c.serviceClient.Delete(c.serviceClient.ServiceURL("lbaas", "flavorprofiles", id), nil)
+	return err
+}
+
+//
+// Flavors
+//
+
+// GetFlavor - get flavor with id
+func (c *Client) GetFlavor(id string) (*Flavor, error) {
+	res := struct {
+		Flavor Flavor `json:"flavor"`
+	}{}
+	_, err := c.serviceClient.Get(c.serviceClient.ServiceURL("lbaas", "flavors", id), &res, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &res.Flavor, nil
+}
+
+// ListFlavors - list flavors matching the filters
+func (c *Client) ListFlavors(filters map[string]string) ([]Flavor, error) {
+	flavors := []Flavor{}
+	if err := c.list(c.serviceClient.ServiceURL("lbaas", "flavors"), "flavors", filters, &flavors); err != nil {
+		return nil, err
+	}
+	return flavors, nil
+}
+
+// CreateFlavor - create a flavor
+func (c *Client) CreateFlavor(f Flavor) (*Flavor, error) {
+	req := struct {
+		Flavor Flavor `json:"flavor"`
+	}{Flavor: f}
+	req.Flavor.ID = ""
+
+	res := struct {
+		Flavor Flavor `json:"flavor"`
+	}{}
+	_, err := c.serviceClient.Post(c.serviceClient.ServiceURL("lbaas", "flavors"), &req, &res, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &res.Flavor, nil
+}
+
+// UpdateFlavor - update name, description and enabled of the flavor f.ID.
+// The flavor profile of a flavor can not be changed.
+func (c *Client) UpdateFlavor(f Flavor) (*Flavor, error) {
+	req := struct {
+		Flavor struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+			Enabled     bool   `json:"enabled"`
+		} `json:"flavor"`
+	}{}
+	req.Flavor.Name = f.Name
+	req.Flavor.Description = f.Description
+	req.Flavor.Enabled = f.Enabled
+
+	res := struct {
+		Flavor Flavor `json:"flavor"`
+	}{}
+	_, err := c.serviceClient.Put(c.serviceClient.ServiceURL("lbaas", "flavors", f.ID), &req, &res, updateOpts)
+	if err != nil {
+		return nil, err
+	}
+	return &res.Flavor, nil
+}
+
+// DeleteFlavor - delete flavor with id
+func (c *Client) DeleteFlavor(id string) error {
+	_, err := c.serviceClient.Delete(c.serviceClient.ServiceURL("lbaas", "flavors", id), nil)
+	return err
+}
+
+//
+// Load balancers
+//
+
+// ListLoadBalancers - list load balancers matching the filters
+func (c *Client) ListLoadBalancers(filters map[string]string) ([]LoadBalancer, error) {
+	loadBalancers := []LoadBalancer{}
+	if err := c.list(c.serviceClient.ServiceURL("lbaas", "loadbalancers"), "loadbalancers", filters, &loadBalancers); err != nil {
+		return nil, err
+	}
+	return loadBalancers, nil
+}
+
+// GetLoadBalancerStats - get the statistics of the load balancer with id
+func (c *Client) GetLoadBalancerStats(id string) (*Stats, error) {
+	res := struct {
+		Stats Stats `json:"stats"`
+	}{}
+	_, err := c.serviceClient.Get(c.serviceClient.ServiceURL("lbaas", "loadbalancers", id, "stats"), &res, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &res.Stats, nil
+}
+
+// CountLoadBalancers - returns the number of load balancers matching the filters
+func (c *Client) CountLoadBalancers(filters map[string]string) (int, error) {
+	loadBalancers := []struct {
+		ID string `json:"id"`
+	}{}
+	if err := c.list(c.serviceClient.ServiceURL("lbaas", "loadbalancers"), "loadbalancers", filters, &loadBalancers); err != nil {
+		return 0, err
+	}
+	return len(loadBalancers), nil
+}
+
+//
+// Listeners
+//
+
+// ListListeners - list listeners matching the filters
+func (c *Client) ListListeners(filters map[string]string) ([]Listener, error) {
+	listeners := []Listener{}
+	if err := c.list(c.serviceClient.ServiceURL("lbaas", "listeners"), "listeners", filters, &listeners); err != nil {
+		return nil, err
+	}
+	return listeners, nil
+}
+
+// GetListenerStats - get the statistics of the listener with id
+func (c *Client) GetListenerStats(id string) (*Stats, error) {
+	res := struct {
+		Stats Stats `json:"stats"`
+	}{}
+	_, err := c.serviceClient.Get(c.serviceClient.ServiceURL("lbaas", "listeners", id, "stats"), &res, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &res.Stats, nil
+}
+
+//
+// Amphorae
+//
+
+// ListAmphorae - list amphorae matching the filters, requires the admin role
+func (c *Client) ListAmphorae(filters map[string]string) ([]Amphora, error) {
+	amphorae := []Amphora{}
+	if err := c.list(c.serviceClient.ServiceURL("octavia", "amphorae"), "amphorae", filters, &amphorae); err != nil {
+		return nil, err
+	}
+	return amphorae, nil
+}
@@ -0,0 +1,256 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	gophercloud "github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/openstack-k8s-operators/octavia-operator/pkg/octavia/octaviatest"
+)
+
+// testClient - client for the API of the server, authenticated against its keystone
+func testClient(t *testing.T, server *octaviatest.Server) *Client {
+	t.Helper()
+
+	providerClient, err := openstack.AuthenticatedClient(gophercloud.AuthOptions{
+		IdentityEndpoint: server.IdentityURL(),
+		Username:         "admin",
+		Password:         "12345678",
+		TenantName:       "admin",
+		DomainName:       "Default",
+	})
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	return NewClient(providerClient, server.URL)
+}
+
+func TestClientFlavorProfiles(t *testing.T) {
+	server := octaviatest.NewServer()
+	defer server.Close()
+	c := testClient(t, server)
+
+	created, err := c.CreateFlavorProfile(FlavorProfile{
+		ID:           "ignored",
+		Name:         "amphora-single",
+		ProviderName: "amphora",
+		FlavorData:   `{"loadbalancer_topology": "SINGLE"}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID == "" || created.ID == "ignored" {
+		t.Errorf("expected a generated ID, got %q", created.ID)
+	}
+
+	fp, err := c.GetFlavorProfile(created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fp, created) {
+		t.Errorf("expected %v, got %v", created, fp)
+	}
+
+	fps, err := c.ListFlavorProfiles(map[string]string{"name": "amphora-single"})
+	if err != nil || len(fps) != 1 {
+		t.Errorf("expected the flavor profile by name, got %v, %v", fps, err)
+	}
+	fps, err = c.ListFlavorProfiles(map[string]string{"name": "other"})
+	if err != nil || len(fps) != 0 {
+		t.Errorf("expected no flavor profile, got %v, %v", fps, err)
+	}
+
+	created.FlavorData = `{"loadbalancer_topology": "ACTIVE_STANDBY"}`
+	updated, err := c.UpdateFlavorProfile(*created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.FlavorData != created.FlavorData || server.FlavorProfiles()[0].FlavorData != created.FlavorData {
+		t.Errorf("flavor data not updated: %v", server.FlavorProfiles())
+	}
+
+	// in use by a flavor
+	flavorID := server.SetFlavor(octaviatest.Flavor{Name: "single", FlavorProfileID: created.ID})
+	if err := c.DeleteFlavorProfile(created.ID); !IsConflict(err) {
+		t.Errorf("expected a conflict, got %v", err)
+	}
+	if err := c.DeleteFlavor(flavorID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.DeleteFlavorProfile(created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetFlavorProfile(created.ID); !IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if err := c.DeleteFlavorProfile(created.ID); !IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestClientFlavors(t *testing.T) {
+	server := octaviatest.NewServer()
+	defer server.Close()
+	c := testClient(t, server)
+
+	profileID := server.SetFlavorProfile(octaviatest.FlavorProfile{Name: "amphora-single", ProviderName: "amphora"})
+
+	created, err := c.CreateFlavor(Flavor{
+		Name:            "single",
+		Description:     "single amphora",
+		FlavorProfileID: profileID,
+		Enabled:         true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := c.GetFlavor(created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(f, created) {
+		t.Errorf("expected %v, got %v", created, f)
+	}
+
+	flavors, err := c.ListFlavors(map[string]string{"flavor_profile_id": profileID})
+	if err != nil || len(flavors) != 1 {
+		t.Errorf("expected the flavor by flavor profile, got %v, %v", flavors, err)
+	}
+
+	// the flavor profile is not part of the update, Octavia rejects it
+	updated, err := c.UpdateFlavor(Flavor{
+		ID:              created.ID,
+		Name:            "single-disabled",
+		FlavorProfileID: "other",
+		Enabled:         false,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Flavor{ID: created.ID, Name: "single-disabled", FlavorProfileID: profileID}
+	if !reflect.DeepEqual(*updated, expected) {
+		t.Errorf("expected %v, got %v", expected, *updated)
+	}
+
+	if err := c.DeleteFlavor(created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetFlavor(created.ID); !IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestClientLoadBalancers(t *testing.T) {
+	server := octaviatest.NewServer()
+	defer server.Close()
+	c := testClient(t, server)
+
+	lbID := server.SetLoadBalancer(octaviatest.LoadBalancer{Name: "lb1", FlavorID: "flavor", ProvisioningStatus: "ACTIVE"})
+	server.SetLoadBalancer(octaviatest.LoadBalancer{Name: "lb2", ProvisioningStatus: "ACTIVE"})
+	server.SetStats(lbID, octaviatest.Stats{BytesIn: 10, BytesOut: 20, ActiveConnections: 1, TotalConnections: 3})
+
+	count, err := c.CountLoadBalancers(map[string]string{"flavor_id": "flavor"})
+	if err != nil || count != 1 {
+		t.Errorf("expected one load balancer using the flavor, got %d, %v", count, err)
+	}
+
+	lbs, err := c.ListLoadBalancers(nil)
+	if err != nil || len(lbs) != 2 {
+		t.Errorf("expected two load balancers, got %v, %v", lbs, err)
+	}
+
+	stats, err := c.GetLoadBalancerStats(lbID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Stats{BytesIn: 10, BytesOut: 20, ActiveConnections: 1, TotalConnections: 3}
+	if *stats != expected {
+		t.Errorf("expected %v, got %v", expected, *stats)
+	}
+	if _, err := c.GetLoadBalancerStats("unknown"); !IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestClientPagination(t *testing.T) {
+	server := octaviatest.NewServer()
+	defer server.Close()
+	server.SetPageSize(2)
+	c := testClient(t, server)
+
+	for i := 0; i < 5; i++ {
+		lbID := server.SetLoadBalancer(octaviatest.LoadBalancer{Name: fmt.Sprintf("lb%d", i), FlavorID: "flavor"})
+		server.SetListener(octaviatest.Listener{Name: fmt.Sprintf("listener%d", i), LoadBalancerID: lbID})
+		server.SetAmphora(octaviatest.Amphora{LoadBalancerID: lbID, Status: "ALLOCATED"})
+	}
+	server.SetLoadBalancer(octaviatest.LoadBalancer{Name: "other"})
+
+	lbs, err := c.ListLoadBalancers(map[string]string{"flavor_id": "flavor"})
+	if err != nil || len(lbs) != 5 {
+		t.Errorf("expected the load balancers of all pages, got %v, %v", lbs, err)
+	}
+	count, err := c.CountLoadBalancers(nil)
+	if err != nil || count != 6 {
+		t.Errorf("expected six load balancers, got %d, %v", count, err)
+	}
+
+	listeners, err := c.ListListeners(nil)
+	if err != nil || len(listeners) != 5 {
+		t.Errorf("expected the listeners of all pages, got %v, %v", listeners, err)
+	} else if len(listeners[0].LoadBalancers) != 1 || listeners[0].LoadBalancers[0].ID == "" {
+		t.Errorf("expected the load balancer of the listener, got %v", listeners[0])
+	}
+
+	amphorae, err := c.ListAmphorae(nil)
+	if err != nil || len(amphorae) != 5 {
+		t.Errorf("expected the amphorae of all pages, got %v, %v", amphorae, err)
+	}
+
+	// the pages got requested one after the other
+	lbRequests := 0
+	for _, r := range server.Requests() {
+		if r == "GET /v2/lbaas/loadbalancers" {
+			lbRequests++
+		}
+	}
+	if lbRequests != 3+3 {
+		t.Errorf("expected three pages of the filtered and of all load balancers, got %d requests", lbRequests)
+	}
+}
+
+func TestFlavorDataEqual(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		equal bool
+	}{
+		{`{"a": 1, "b": "x"}`, `{"b":"x","a":1}`, true},
+		{`{"a": 1}`, `{"a": 2}`, false},
+		{`invalid`, `invalid`, true},
+		{`invalid`, `{}`, false},
+	}
+
+	for _, tc := range tests {
+		if equal := FlavorDataEqual(tc.a, tc.b); equal != tc.equal {
+			t.Errorf("FlavorDataEqual(%s, %s): expected %v", tc.a, tc.b, tc.equal)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"strconv"
+	"strings"
+
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+)
+
+// GetServiceConfigParameters - returns the template parameters for octavia.conf which are derived
+// from the OctaviaAPI spec. Options in CustomServiceConfig override them, as custom.conf gets
+// loaded from the config dir after octavia.conf.
+func GetServiceConfigParameters(instance *octaviav1.OctaviaAPI) map[string]interface{} {
+	templateParameters := map[string]interface{}{
+		"ServiceUser": instance.Spec.ServiceUser,
+	}
+
+	// httpd.conf
+	templateParameters["APIPort"] = GetAPIPort(instance)
+	templateParameters["APIWorkers"] = defaultInt32(instance.Spec.APIWorkers, 5)
+	templateParameters["APIThreads"] = defaultInt32(instance.Spec.APIThreads, 1)
+	templateParameters["APITimeout"] = defaultInt32(instance.Spec.APITimeout, 60)
+	templateParameters["VirtualHosts"] = GetVirtualHosts(instance)
+
+	// provider drivers
+	templateParameters["EnabledProviderDrivers"] = GetEnabledProviderDrivers(instance.Spec.EnabledProviders)
+	if len(instance.Spec.EnabledProviders) > 0 {
+		templateParameters["DefaultProviderDriver"] = string(instance.Spec.EnabledProviders[0])
+	}
+	templateParameters["OVNEnabled"] = instance.IsProviderEnabled(octaviav1.ProviderDriverOVN)
+
+	// [api_settings]
+	// the optional options are empty if unset and do not get rendered, to keep the Octavia defaults
+	apiSettings := instance.Spec.APISettings
+	templateParameters["DefaultListenerTLSVersions"] = joinTLSVersions(apiSettings.DefaultListenerTLSVersions)
+	templateParameters["DefaultPoolTLSVersions"] = joinTLSVersions(apiSettings.DefaultPoolTLSVersions)
+	templateParameters["AllowTLSTerminatedListeners"] = optionalBool(apiSettings.AllowTLSTerminatedListeners)
+	templateParameters["AllowPingHealthMonitors"] = optionalBool(apiSettings.AllowPingHealthMonitors)
+	templateParameters["AllowPrometheusListeners"] = optionalBool(apiSettings.AllowPrometheusListeners)
+	templateParameters["PaginationMaxLimit"] = apiSettings.PaginationMaxLimit
+
+	// [controller_worker]
+	templateParameters["ControllerWorkers"] = instance.Spec.ControllerWorker.Workers
+	templateParameters["LoadBalancerTopology"] = instance.Spec.ControllerWorker.LoadBalancerTopology
+
+	// [haproxy_amphora]
+	haproxy := instance.Spec.HAProxyAmphora
+	templateParameters["TimeoutClientData"] = optionalInt32(haproxy.TimeoutClientData)
+	templateParameters["TimeoutMemberConnect"] = optionalInt32(haproxy.TimeoutMemberConnect)
+	templateParameters["TimeoutMemberData"] = optionalInt32(haproxy.TimeoutMemberData)
+	templateParameters["TimeoutTCPInspect"] = optionalInt32(haproxy.TimeoutTCPInspect)
+
+	// [service_auth]
+	templateParameters["ApplicationCredential"] = instance.Spec.ApplicationCredential.Enabled
+
+	// [nova]
+	templateParameters["EnableAntiAffinity"] = optionalBool(instance.Spec.Nova.EnableAntiAffinity)
+	templateParameters["AntiAffinityPolicy"] = instance.Spec.Nova.AntiAffinityPolicy
+
+	return templateParameters
+}
+
+func joinTLSVersions(versions []octaviav1.TLSVersion) string {
+	v := make([]string, len(versions))
+	for i, version := range versions {
+		v[i] = string(version)
+	}
+	return strings.Join(v, ",")
+}
+
+// GetAPIPort - returns the port the API listens on
+func GetAPIPort(instance *octaviav1.OctaviaAPI) int32 {
+	return defaultInt32(instance.Spec.APIPort, OctaviaDefaultPort)
+}
+
+func defaultInt32(value int32, defaultValue int32) int32 {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// optionalBool - returns the value as string, or an empty string if it is not set
+func optionalBool(value *bool) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatBool(*value)
+}
+
+// optionalInt32 - returns the value as string, or an empty string if it is not set
+func optionalInt32(value *int32) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatInt(int64(*value), 10)
+}
@@ -0,0 +1,91 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"path/filepath"
+	"testing"
+
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+)
+
+func renderOctaviaConf(t *testing.T, instance *octaviav1.OctaviaAPI) *IniFile {
+	t.Helper()
+
+	templates, err := filepath.Abs("../../templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("OPERATOR_TEMPLATES", templates)
+
+	instance.Kind = "OctaviaAPI"
+	cms := ServiceConfigTemplates(instance, map[string]string{}, GetServiceConfigParameters(instance))
+	data, err := RenderTemplate(cms[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ParseIni(data["octavia.conf"])
+}
+
+func TestOptionalConfigOptions(t *testing.T) {
+	options := []struct {
+		section string
+		key     string
+	}{
+		{"api_settings", "allow_tls_terminated_listeners"},
+		{"api_settings", "allow_ping_health_monitors"},
+		{"api_settings", "allow_prometheus_listeners"},
+		{"haproxy_amphora", "timeout_client_data"},
+		{"haproxy_amphora", "timeout_member_connect"},
+		{"haproxy_amphora", "timeout_member_data"},
+		{"haproxy_amphora", "timeout_tcp_inspect"},
+		{"nova", "enable_anti_affinity"},
+	}
+
+	t.Run("unset", func(t *testing.T) {
+		instance := testInstance()
+		conf := renderOctaviaConf(t, instance)
+		for _, o := range options {
+			if v, ok := conf.Get(o.section, o.key); ok {
+				t.Errorf("%s/%s: expected the Octavia default, got %s", o.section, o.key, v)
+			}
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		disabled := false
+		noTimeout := int32(0)
+		instance := testInstance()
+		instance.Spec.APISettings.AllowTLSTerminatedListeners = &disabled
+		instance.Spec.APISettings.AllowPingHealthMonitors = &disabled
+		instance.Spec.APISettings.AllowPrometheusListeners = &disabled
+		instance.Spec.HAProxyAmphora.TimeoutClientData = &noTimeout
+		instance.Spec.HAProxyAmphora.TimeoutMemberConnect = &noTimeout
+		instance.Spec.HAProxyAmphora.TimeoutMemberData = &noTimeout
+		instance.Spec.HAProxyAmphora.TimeoutTCPInspect = &noTimeout
+		instance.Spec.Nova.EnableAntiAffinity = &disabled
+
+		conf := renderOctaviaConf(t, instance)
+		for _, o := range options {
+			v, ok := conf.Get(o.section, o.key)
+			if !ok {
+				t.Errorf("%s/%s: not rendered", o.section, o.key)
+			} else if v != "false" && v != "0" {
+				t.Errorf("%s/%s: unexpected value %s", o.section, o.key, v)
+			}
+		}
+	})
+}
@@ -0,0 +1,123 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	keystonev1 "github.com/openstack-k8s-operators/keystone-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/lib-common/modules/common"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+)
+
+// ServiceConfigTemplates - returns the templates of the configmaps holding the scripts and
+// the service configuration of the OctaviaAPI
+// - %-scripts configmap holding scripts to e.g. bootstrap the service
+// - %-config-data configmap holding minimal octavia config required to get the service up,
+// user can add additional files to be added to the service
+func ServiceConfigTemplates(
+	instance *octaviav1.OctaviaAPI,
+	labels map[string]string,
+	templateParameters map[string]interface{},
+) []util.Template {
+	// customData hold any customization for the service.
+	// custom.conf is going to /etc/<service>/<service>.conf.d
+	// all other files get placed into /etc/<service> to allow overwrite of e.g. logging.conf or policy.json
+	// TODO: make sure custom.conf can not be overwritten
+	customData := map[string]string{common.CustomServiceConfigFileName: instance.Spec.CustomServiceConfig}
+	for key, data := range instance.Spec.DefaultConfigOverwrite {
+		customData[key] = data
+	}
+
+	return []util.Template{
+		// ScriptsConfigMap
+		{
+			Name:               fmt.Sprintf("%s-scripts", instance.Name),
+			Namespace:          instance.Namespace,
+			Type:               util.TemplateTypeScripts,
+			InstanceType:       instance.Kind,
+			AdditionalTemplate: map[string]string{"common.sh": "/common/common.sh"},
+			Labels:             labels,
+		},
+		// ConfigMap
+		{
+			Name:          fmt.Sprintf("%s-config-data", instance.Name),
+			Namespace:     instance.Namespace,
+			Type:          util.TemplateTypeConfig,
+			InstanceType:  instance.Kind,
+			CustomData:    customData,
+			ConfigOptions: templateParameters,
+			Labels:        labels,
+		},
+	}
+}
+
+// GetKeystoneParameters - returns the template parameters derived from the KeystoneAPI
+func GetKeystoneParameters(keystoneAPI *keystonev1.KeystoneAPI) (map[string]interface{}, error) {
+	publicURL, err := keystoneAPI.GetEndpoint(endpoint.EndpointPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"KeystonePublicURL": publicURL,
+	}, nil
+}
+
+// RenderTemplate - renders the data of the configmap of the template like the configmap
+// module does, custom data overwrites rendered files with the same name
+func RenderTemplate(t util.Template) (map[string]string, error) {
+	data, err := util.GetTemplateData(t)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range t.CustomData {
+		data[k] = v
+	}
+	return data, nil
+}
+
+// MergeConfigDir - merges the files of a config dir into the merged files, like merge_config_dir
+// of the init container does. Existing files, except json files, get merged using the crudini
+// --merge semantics, all others get copied.
+func MergeConfigDir(merged map[string]string, files map[string]string) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		base, ok := merged[name]
+		if !ok || strings.EqualFold(filepath.Ext(name), ".json") {
+			merged[name] = files[name]
+			continue
+		}
+		ini := ParseIni(base)
+		ini.Merge(ParseIni(files[name]))
+		merged[name] = ini.String()
+	}
+}
+
+// DatabaseConnection - returns the database connection the init container sets in octavia.conf
+func DatabaseConnection(user string, password string, host string, database string) string {
+	return fmt.Sprintf("mysql+pymysql://%s:%s@%s/%s", user, password, FormatHost(host), database)
+}
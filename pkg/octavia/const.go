@@ -23,15 +23,9 @@ const (
 	// DatabaseName -
 	DatabaseName = "octavia"
 
-	// OctaviaAdminPort -
-	OctaviaAdminPort int32 = 9876
-	// OctaviaPublicPort -
-	OctaviaPublicPort int32 = 9876
-	// OctaviaInternalPort -
-	OctaviaInternalPort int32 = 9876
+	// OctaviaDefaultPort - port used if spec.apiPort is not set
+	OctaviaDefaultPort int32 = 9876
 
-	// KollaDbSyncConfig -
-	KollaDbSyncConfig = "/var/lib/config-data/merged/octavia-api-db-sync.json"
-	// KollaConfig -
-	KollaConfig = "/var/lib/config-data/merged/octavia-api-config.json"
+	// DebugCommand - keeps the container running without starting the service
+	DebugCommand = "/bin/sleep infinity"
 )
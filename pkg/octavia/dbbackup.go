@@ -0,0 +1,304 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DBBackupCommand - dumps the DB into the target and applies the retention
+	DBBackupCommand = "/usr/local/bin/container-scripts/db_backup.sh"
+
+	// DBRestoreCommand - restores the DB from the dump in the target
+	DBRestoreCommand = "/usr/local/bin/container-scripts/db_restore.sh"
+
+	// DBBackupMountPath - mount path of the PVC target
+	DBBackupMountPath = "/var/lib/octavia-backup"
+
+	// ObjectStoreAccessKeyIDKey - key of the access key ID in the object store secret
+	ObjectStoreAccessKeyIDKey = "AccessKeyID"
+
+	// ObjectStoreSecretAccessKeyKey - key of the secret access key in the object store secret
+	ObjectStoreSecretAccessKeyKey = "SecretAccessKey"
+)
+
+// dbBackupJobBackoffLimit - retries of the backup and restore jobs before they are failed
+var dbBackupJobBackoffLimit int32 = 2
+
+// ValidateDBBackupTarget - returns an error unless exactly one target is set
+func ValidateDBBackupTarget(target octaviav1.OctaviaDBBackupTarget) error {
+	if (target.PersistentVolumeClaim == "") == (target.ObjectStore == nil) {
+		return fmt.Errorf("exactly one of persistentVolumeClaim and objectStore has to be set as target")
+	}
+	return nil
+}
+
+// DBBackupFileName - name of the dump of the backup in the target. It starts with the creation
+// time, the retention keeps the dumps with the newest names.
+func DBBackupFileName(backup *octaviav1.OctaviaDBBackup) string {
+	return fmt.Sprintf("%s-%s-%s.sql.gz",
+		DatabaseName, backup.CreationTimestamp.UTC().Format("20060102150405"), backup.Name)
+}
+
+// DBBackupLocation - the PVC or URL of the dump
+func DBBackupLocation(target octaviav1.OctaviaDBBackupTarget, fileName string) string {
+	if target.ObjectStore != nil {
+		return strings.TrimSuffix(target.ObjectStore.URL, "/") + "/" + fileName
+	}
+	return fmt.Sprintf("pvc://%s/%s", target.PersistentVolumeClaim, fileName)
+}
+
+// DBBackupJob - the job dumping the DB of the OctaviaAPI into the target of the backup
+func DBBackupJob(
+	backup *octaviav1.OctaviaDBBackup,
+	api *octaviav1.OctaviaAPI,
+	labels map[string]string,
+) *batchv1.Job {
+	envs := dbBackupEnv(backup.Spec.Target, api, DBBackupFileName(backup))
+	envs = append(envs, corev1.EnvVar{Name: "Retention", Value: strconv.Itoa(backup.Spec.Retention)})
+
+	podLabels := JobPodLabels(DBBackupComponent)
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      backup.Name + "-db-backup",
+			Namespace: backup.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &dbBackupJobBackoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podLabels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: ServiceAccount,
+					SecurityContext:    getPodSecurityContext(),
+					Containers: []corev1.Container{
+						dbBackupContainer("db-backup", backup.Spec.ContainerImage, DBBackupCommand, envs, backup.Spec.Target),
+					},
+					Volumes: getDBBackupVolumes(api.Name, backup.Spec.Target),
+				},
+			},
+		},
+	}
+
+	applyScheduling(api, &job.Spec.Template.Spec, podLabels)
+
+	return job
+}
+
+// PreUpgradeBackupJob - the job dumping the DB into the PVC of the pre-upgrade backup started at startTime
+func PreUpgradeBackupJob(
+	instance *octaviav1.OctaviaAPI,
+	startTime metav1.Time,
+	labels map[string]string,
+) *batchv1.Job {
+	job := DBBackupJob(PreUpgradeBackup(instance, startTime), instance, labels)
+	job.Name = fmt.Sprintf("%s-pre-upgrade-%s", ServiceName, startTime.UTC().Format("20060102150405"))
+
+	return job
+}
+
+// PreUpgradeBackup - the pre-upgrade backup settings of the OctaviaAPI as backup started at
+// startTime. Its dumps share the naming and retention with the ones of OctaviaDBBackups.
+func PreUpgradeBackup(instance *octaviav1.OctaviaAPI, startTime metav1.Time) *octaviav1.OctaviaDBBackup {
+	return &octaviav1.OctaviaDBBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "pre-upgrade",
+			Namespace:         instance.Namespace,
+			CreationTimestamp: startTime,
+		},
+		Spec: octaviav1.OctaviaDBBackupSpec{
+			ContainerImage: instance.Spec.PreUpgradeBackup.ContainerImage,
+			Target: octaviav1.OctaviaDBBackupTarget{
+				PersistentVolumeClaim: instance.Spec.PreUpgradeBackup.PersistentVolumeClaim,
+			},
+			Retention: instance.Spec.PreUpgradeBackup.Retention,
+		},
+	}
+}
+
+// DBRestoreJob - the db-sync job of the OctaviaAPI, which restores the dump of the backup
+// before it syncs the DB
+func DBRestoreJob(
+	restore *octaviav1.OctaviaDBRestore,
+	backup *octaviav1.OctaviaDBBackup,
+	api *octaviav1.OctaviaAPI,
+	labels map[string]string,
+) *batchv1.Job {
+	job := DbSyncJob(api, labels)
+	job.Name = restore.Name + "-db-restore"
+	job.Namespace = restore.Namespace
+	job.Spec.BackoffLimit = &dbBackupJobBackoffLimit
+	// restoring takes longer than the db-sync, and a removed job would restore the dump again
+	job.Spec.ActiveDeadlineSeconds = nil
+	job.Spec.TTLSecondsAfterFinished = nil
+
+	podSpec := &job.Spec.Template.Spec
+	envs := dbBackupEnv(backup.Spec.Target, api, backup.Status.FileName)
+	podSpec.InitContainers = append([]corev1.Container{
+		dbBackupContainer("db-restore", restore.Spec.ContainerImage, DBRestoreCommand, envs, backup.Spec.Target),
+	}, podSpec.InitContainers...)
+	for _, volume := range getDBBackupVolumes(api.Name, backup.Spec.Target) {
+		if volume.Name == "backup" {
+			podSpec.Volumes = append(podSpec.Volumes, volume)
+		}
+	}
+
+	return job
+}
+
+// dbBackupContainer - container running the backup or restore script with the mysql client
+func dbBackupContainer(
+	name string,
+	image string,
+	command string,
+	envs []corev1.EnvVar,
+	target octaviav1.OctaviaDBBackupTarget,
+) corev1.Container {
+	return corev1.Container{
+		Name: name,
+		Command: []string{
+			"/bin/bash",
+		},
+		Args:            []string{"-c", command},
+		Image:           image,
+		Env:             envs,
+		SecurityContext: getContainerSecurityContext(),
+		VolumeMounts:    getDBBackupVolumeMounts(target),
+	}
+}
+
+// dbBackupEnv - the DB credentials of the OctaviaAPI and the target. The mysql client reads
+// the password from MYSQL_PWD, to not pass it on the command line.
+func dbBackupEnv(
+	target octaviav1.OctaviaDBBackupTarget,
+	api *octaviav1.OctaviaAPI,
+	fileName string,
+) []corev1.EnvVar {
+	envVars := map[string]env.Setter{}
+	envVars["DatabaseHost"] = env.SetValue(api.Status.DatabaseHostname)
+	envVars["DatabaseUser"] = env.SetValue(api.Spec.DatabaseUser)
+	envVars["DatabaseName"] = env.SetValue(DatabaseName)
+	envVars["BackupFile"] = env.SetValue(fileName)
+	if target.ObjectStore != nil {
+		envVars["BackupDir"] = env.SetValue("/tmp")
+		envVars["ObjectStoreURL"] = env.SetValue(strings.TrimSuffix(target.ObjectStore.URL, "/"))
+		envVars["ObjectStoreRegion"] = env.SetValue(target.ObjectStore.Region)
+	} else {
+		envVars["BackupDir"] = env.SetValue(DBBackupMountPath)
+	}
+
+	envs := []corev1.EnvVar{
+		secretEnvVar("MYSQL_PWD", api.Spec.Secret, api.Spec.PasswordSelectors.Database),
+	}
+	if target.ObjectStore != nil {
+		envs = append(envs,
+			secretEnvVar("ObjectStoreAccessKeyID", target.ObjectStore.Secret, ObjectStoreAccessKeyIDKey),
+			secretEnvVar("ObjectStoreSecretAccessKey", target.ObjectStore.Secret, ObjectStoreSecretAccessKeyKey))
+	}
+
+	return env.MergeEnvs(envs, envVars)
+}
+
+// secretEnvVar - env var name set from the key of the secret
+func secretEnvVar(name string, secret string, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: secret,
+				},
+				Key: key,
+			},
+		},
+	}
+}
+
+// getDBBackupVolumes - the scripts of the OctaviaAPI with name, a writable /tmp and the PVC target
+func getDBBackupVolumes(name string, target octaviav1.OctaviaDBBackupTarget) []corev1.Volume {
+	var scriptsVolumeDefaultMode int32 = 0755
+
+	volumes := []corev1.Volume{
+		{
+			Name: "scripts",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					DefaultMode: &scriptsVolumeDefaultMode,
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: name + "-scripts",
+					},
+				},
+			},
+		},
+		{
+			Name: "tmp",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{Medium: ""},
+			},
+		},
+	}
+	if target.PersistentVolumeClaim != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: "backup",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: target.PersistentVolumeClaim,
+				},
+			},
+		})
+	}
+
+	return volumes
+}
+
+// getDBBackupVolumeMounts - VolumeMounts of the backup and restore containers
+func getDBBackupVolumeMounts(target octaviav1.OctaviaDBBackupTarget) []corev1.VolumeMount {
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "scripts",
+			MountPath: "/usr/local/bin/container-scripts",
+			ReadOnly:  true,
+		},
+		{
+			Name:      "tmp",
+			MountPath: "/tmp",
+			ReadOnly:  false,
+		},
+	}
+	if target.PersistentVolumeClaim != "" {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "backup",
+			MountPath: DBBackupMountPath,
+			ReadOnly:  false,
+		})
+	}
+
+	return volumeMounts
+}
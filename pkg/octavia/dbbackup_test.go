@@ -0,0 +1,129 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"testing"
+	"time"
+
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// testBackup - OctaviaDBBackup with the defaults the CRD would set
+func testBackup(target octaviav1.OctaviaDBBackupTarget) *octaviav1.OctaviaDBBackup {
+	return &octaviav1.OctaviaDBBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "pre-upgrade",
+			Namespace:         "openstack",
+			CreationTimestamp: metav1.NewTime(time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC)),
+		},
+		Spec: octaviav1.OctaviaDBBackupSpec{
+			ContainerImage: "quay.io/tripleozedcentos9/openstack-mariadb:current-tripleo",
+			Target:         target,
+			Retention:      7,
+		},
+	}
+}
+
+func TestValidateDBBackupTarget(t *testing.T) {
+	objectStore := &octaviav1.OctaviaDBBackupObjectStore{URL: "https://s3.example.com/backups", Secret: "s3"}
+
+	tests := []struct {
+		name   string
+		target octaviav1.OctaviaDBBackupTarget
+		valid  bool
+	}{
+		{name: "no target"},
+		{name: "pvc", target: octaviav1.OctaviaDBBackupTarget{PersistentVolumeClaim: "backup"}, valid: true},
+		{name: "object store", target: octaviav1.OctaviaDBBackupTarget{ObjectStore: objectStore}, valid: true},
+		{name: "both", target: octaviav1.OctaviaDBBackupTarget{PersistentVolumeClaim: "backup", ObjectStore: objectStore}},
+	}
+
+	for _, tc := range tests {
+		if err := ValidateDBBackupTarget(tc.target); (err == nil) != tc.valid {
+			t.Errorf("%s: expected valid %v, got %v", tc.name, tc.valid, err)
+		}
+	}
+}
+
+func TestDBBackupLocation(t *testing.T) {
+	backup := testBackup(octaviav1.OctaviaDBBackupTarget{PersistentVolumeClaim: "backup"})
+	fileName := DBBackupFileName(backup)
+	if fileName != "octavia-20221101120000-pre-upgrade.sql.gz" {
+		t.Errorf("unexpected file name %s", fileName)
+	}
+
+	if location := DBBackupLocation(backup.Spec.Target, fileName); location != "pvc://backup/"+fileName {
+		t.Errorf("unexpected PVC location %s", location)
+	}
+
+	target := octaviav1.OctaviaDBBackupTarget{
+		ObjectStore: &octaviav1.OctaviaDBBackupObjectStore{URL: "https://s3.example.com/backups/"},
+	}
+	if location := DBBackupLocation(target, fileName); location != "https://s3.example.com/backups/"+fileName {
+		t.Errorf("unexpected object store location %s", location)
+	}
+}
+
+func TestDBBackupJobs(t *testing.T) {
+	labels := map[string]string{"service": ServiceName}
+	api := testInstance()
+
+	pvcBackup := testBackup(octaviav1.OctaviaDBBackupTarget{PersistentVolumeClaim: "octavia-backup"})
+	assertGolden(t, "dbbackup_job_pvc", DBBackupJob(pvcBackup, api, labels))
+
+	objectStoreBackup := testBackup(octaviav1.OctaviaDBBackupTarget{
+		ObjectStore: &octaviav1.OctaviaDBBackupObjectStore{
+			URL:    "https://s3.example.com/octavia-backups",
+			Region: "us-east-1",
+			Secret: "octavia-backup-s3",
+		},
+	})
+	assertGolden(t, "dbbackup_job_objectstore", DBBackupJob(objectStoreBackup, api, labels))
+
+	pvcBackup.Status.FileName = DBBackupFileName(pvcBackup)
+	restore := &octaviav1.OctaviaDBRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rollback",
+			Namespace: "openstack",
+		},
+		Spec: octaviav1.OctaviaDBRestoreSpec{
+			Backup:         pvcBackup.Name,
+			ContainerImage: "quay.io/tripleozedcentos9/openstack-mariadb:current-tripleo",
+		},
+	}
+	assertGolden(t, "dbrestore_job", DBRestoreJob(restore, pvcBackup, api, labels))
+}
+
+func TestPreUpgradeBackupJob(t *testing.T) {
+	instance := testInstance()
+	instance.Spec.PreUpgradeBackup = octaviav1.OctaviaPreUpgradeBackup{
+		Enabled:               true,
+		PersistentVolumeClaim: "octavia-backup",
+		Retention:             3,
+		ContainerImage:        "quay.io/tripleozedcentos9/openstack-mariadb:current-tripleo",
+	}
+	startTime := metav1.NewTime(time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC))
+
+	backup := PreUpgradeBackup(instance, startTime)
+	if location := DBBackupLocation(backup.Spec.Target, DBBackupFileName(backup)); location != "pvc://octavia-backup/octavia-20221101120000-pre-upgrade.sql.gz" {
+		t.Errorf("unexpected location %s", location)
+	}
+
+	assertGolden(t, "preupgrade_backup_job", PreUpgradeBackupJob(instance, startTime, map[string]string{"service": ServiceName}))
+}
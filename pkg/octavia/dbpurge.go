@@ -0,0 +1,103 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"fmt"
+
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DBPurgeCommand - purges the deleted load balancers and amphorae older than the age in days
+	DBPurgeCommand = "/usr/local/bin/container-scripts/db_purge.sh"
+)
+
+// DbPurgeCronJob - the cronjob purging the DB, runs the same init container as the db-sync job
+func DbPurgeCronJob(
+	instance *octaviav1.OctaviaAPI,
+	labels map[string]string,
+) *batchv1.CronJob {
+	initVolumeMounts := getInitVolumeMounts()
+	volumeMounts := getVolumeMounts()
+	volumes := getVolumes(instance.Name)
+
+	args := []string{"-c", fmt.Sprintf("%s %d", DBPurgeCommand, instance.Spec.DBPurge.Age)}
+
+	// the finished jobs get removed like the db-sync job, unless they should be preserved.
+	// Failed jobs are kept to check the logs.
+	var successfulJobsHistoryLimit int32
+	var failedJobsHistoryLimit int32 = 1
+	if instance.Spec.PreserveJobs {
+		successfulJobsHistoryLimit = 3
+	}
+
+	podLabels := JobPodLabels(DBPurgeComponent)
+
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ServiceName + "-db-purge",
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   instance.Spec.DBPurge.Schedule,
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: &successfulJobsHistoryLimit,
+			FailedJobsHistoryLimit:     &failedJobsHistoryLimit,
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: podLabels,
+						},
+						Spec: corev1.PodSpec{
+							RestartPolicy:      "OnFailure",
+							ServiceAccountName: ServiceAccount,
+							SecurityContext:    getPodSecurityContext(),
+							Containers: []corev1.Container{
+								{
+									Name: ServiceName + "-db-purge",
+									Command: []string{
+										"/bin/bash",
+									},
+									Args:            args,
+									Image:           instance.Spec.ContainerImage,
+									SecurityContext: getContainerSecurityContext(),
+									VolumeMounts:    volumeMounts,
+								},
+							},
+							Volumes: volumes,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	podSpec := &cronJob.Spec.JobTemplate.Spec.Template.Spec
+	applyScheduling(instance, podSpec, podLabels)
+	podSpec.InitContainers = initContainer(getDBJobInitContainerDetails(instance, initVolumeMounts))
+
+	return cronJob
+}
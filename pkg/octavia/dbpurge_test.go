@@ -0,0 +1,32 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"testing"
+)
+
+func TestDbPurgeCronJob(t *testing.T) {
+	labels := map[string]string{"service": ServiceName}
+
+	instance := testInstance()
+	assertGolden(t, "dbpurge_cronjob", DbPurgeCronJob(instance, labels))
+
+	instance.Spec.PreserveJobs = true
+	instance.Spec.DBPurge.Schedule = "0 3 * * 0"
+	instance.Spec.DBPurge.Age = 7
+	assertGolden(t, "dbpurge_cronjob_preserve", DbPurgeCronJob(instance, labels))
+}
@@ -18,8 +18,6 @@ package octavia
 import (
 	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
 
-	"github.com/openstack-k8s-operators/lib-common/modules/common"
-	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -27,7 +25,7 @@ import (
 
 const (
 	// DBSyncCommand -
-	DBSyncCommand = "/usr/local/bin/kolla_set_configs && /usr/local/bin/kolla_start"
+	DBSyncCommand = "/usr/local/bin/container-scripts/bootstrap.sh"
 )
 
 // DbSyncJob func
@@ -35,21 +33,18 @@ func DbSyncJob(
 	instance *octaviav1.OctaviaAPI,
 	labels map[string]string,
 ) *batchv1.Job {
-	runAsUser := int64(0)
 	initVolumeMounts := getInitVolumeMounts()
 	volumeMounts := getVolumeMounts()
 	volumes := getVolumes(instance.Name)
 
 	args := []string{"-c"}
 	if instance.Spec.Debug.DBSync {
-		args = append(args, common.DebugCommand)
+		args = append(args, DebugCommand)
 	} else {
 		args = append(args, DBSyncCommand)
 	}
 
-	envVars := map[string]env.Setter{}
-	envVars["KOLLA_CONFIG_FILE"] = env.SetValue(KollaDbSyncConfig)
-	envVars["KOLLA_CONFIG_STRATEGY"] = env.SetValue("COPY_ALWAYS")
+	podLabels := JobPodLabels(DBSyncComponent)
 
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
@@ -58,23 +53,28 @@ func DbSyncJob(
 			Labels:    labels,
 		},
 		Spec: batchv1.JobSpec{
+			BackoffLimit:            instance.Spec.DBSyncJob.BackoffLimit,
+			ActiveDeadlineSeconds:   instance.Spec.DBSyncJob.ActiveDeadlineSeconds,
+			TTLSecondsAfterFinished: instance.Spec.DBSyncJob.TTLSecondsAfterFinished,
 			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podLabels,
+				},
 				Spec: corev1.PodSpec{
-					RestartPolicy:      "OnFailure",
+					// failed pods are kept to report their logs, the job retries with new pods
+					RestartPolicy:      corev1.RestartPolicyNever,
 					ServiceAccountName: ServiceAccount,
+					SecurityContext:    getPodSecurityContext(),
 					Containers: []corev1.Container{
 						{
 							Name: ServiceName + "-db-sync",
 							Command: []string{
 								"/bin/bash",
 							},
-							Args:  args,
-							Image: instance.Spec.ContainerImage,
-							SecurityContext: &corev1.SecurityContext{
-								RunAsUser: &runAsUser,
-							},
-							Env:          env.MergeEnvs([]corev1.EnvVar{}, envVars),
-							VolumeMounts: volumeMounts,
+							Args:            args,
+							Image:           instance.Spec.ContainerImage,
+							SecurityContext: getContainerSecurityContext(),
+							VolumeMounts:    volumeMounts,
 						},
 					},
 					Volumes: volumes,
@@ -83,7 +83,16 @@ func DbSyncJob(
 		},
 	}
 
-	initContainerDetails := APIDetails{
+	applyScheduling(instance, &job.Spec.Template.Spec, podLabels)
+
+	job.Spec.Template.Spec.InitContainers = initContainer(getDBJobInitContainerDetails(instance, initVolumeMounts))
+
+	return job
+}
+
+// getDBJobInitContainerDetails - init container settings of the jobs running DB commands
+func getDBJobInitContainerDetails(instance *octaviav1.OctaviaAPI, initVolumeMounts []corev1.VolumeMount) APIDetails {
+	return APIDetails{
 		ContainerImage:       instance.Spec.ContainerImage,
 		DatabaseHost:         instance.Status.DatabaseHostname,
 		DatabaseUser:         instance.Spec.DatabaseUser,
@@ -93,7 +102,4 @@ func DbSyncJob(
 		UserPasswordSelector: instance.Spec.PasswordSelectors.Service,
 		VolumeMounts:         initVolumeMounts,
 	}
-	job.Spec.Template.Spec.InitContainers = initContainer(initContainerDetails)
-
-	return job
 }
@@ -18,6 +18,7 @@ package octavia
 import (
 	"github.com/openstack-k8s-operators/lib-common/modules/common"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/affinity"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
 	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
 
@@ -29,7 +30,9 @@ import (
 
 const (
 	// ServiceCommand -
-	ServiceCommand = "/usr/local/bin/kolla_set_configs && /usr/local/bin/kolla_start"
+	ServiceCommand = "/usr/sbin/httpd -DFOREGROUND"
+	// DriverAgentCommand -
+	DriverAgentCommand = "/usr/bin/octavia-driver-agent --config-file /etc/octavia/octavia.conf --config-dir /etc/octavia/octavia.conf.d"
 )
 
 // Deployment func
@@ -38,10 +41,9 @@ func Deployment(
 	configHash string,
 	labels map[string]string,
 ) *appsv1.Deployment {
-	runAsUser := int64(0)
 	initVolumeMounts := getInitVolumeMounts()
-	volumeMounts := getVolumeMounts()
-	volumes := getVolumes(instance.Name)
+	volumeMounts := append(getAPIVolumeMounts(), getTLSVolumeMounts(instance)...)
+	volumes := append(getVolumes(instance.Name), getTLSVolumes(instance)...)
 
 	livenessProbe := &corev1.Probe{
 		// TODO might need tuning
@@ -58,7 +60,7 @@ func Deployment(
 
 	args := []string{"-c"}
 	if instance.Spec.Debug.Service {
-		args = append(args, common.DebugCommand)
+		args = append(args, DebugCommand)
 		livenessProbe.Exec = &corev1.ExecAction{
 			Command: []string{
 				"/bin/true",
@@ -76,19 +78,28 @@ func Deployment(
 		//
 		// https://kubernetes.io/docs/tasks/configure-pod-container/configure-liveness-readiness-startup-probes/
 		//
+		// the probes use the virtual host of the internal endpoint
+		probePort := GetEndpointPort(instance, endpoint.EndpointInternal)
+		var probeScheme corev1.URIScheme
+		if GetEndpointScheme(instance, endpoint.EndpointInternal) == "https" {
+			probeScheme = corev1.URISchemeHTTPS
+		}
 		livenessProbe.HTTPGet = &corev1.HTTPGetAction{
-			Path: "/healthcheck",
-			Port: intstr.IntOrString{Type: intstr.Int, IntVal: int32(OctaviaPublicPort)},
+			Path:   "/healthcheck",
+			Port:   intstr.IntOrString{Type: intstr.Int, IntVal: probePort},
+			Scheme: probeScheme,
 		}
 		readinessProbe.HTTPGet = &corev1.HTTPGetAction{
-			Path: "/healthcheck",
-			Port: intstr.IntOrString{Type: intstr.Int, IntVal: int32(OctaviaPublicPort)},
+			Path:   "/healthcheck",
+			Port:   intstr.IntOrString{Type: intstr.Int, IntVal: probePort},
+			Scheme: probeScheme,
 		}
 	}
 
+	// scaled to zero in maintenance mode
+	replicas := instance.GetReplicas()
+
 	envVars := map[string]env.Setter{}
-	envVars["KOLLA_CONFIG_FILE"] = env.SetValue(KollaConfig)
-	envVars["KOLLA_CONFIG_STRATEGY"] = env.SetValue("COPY_ALWAYS")
 	envVars["CONFIG_HASH"] = env.SetValue(configHash)
 
 	// TODO(tweining): Implement container deployment
@@ -101,29 +112,29 @@ func Deployment(
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
-			Replicas: &instance.Spec.Replicas,
+			Replicas: &replicas,
+			Strategy: DeploymentStrategy(instance),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: labels,
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: ServiceAccount,
+					SecurityContext:    getPodSecurityContext(),
 					Containers: []corev1.Container{
 						{
 							Name: ServiceName + "-api",
 							Command: []string{
 								"/bin/bash",
 							},
-							Args:  args,
-							Image: instance.Spec.ContainerImage,
-							SecurityContext: &corev1.SecurityContext{
-								RunAsUser: &runAsUser,
-							},
-							Env:            env.MergeEnvs([]corev1.EnvVar{}, envVars),
-							VolumeMounts:   volumeMounts,
-							Resources:      instance.Spec.Resources,
-							ReadinessProbe: readinessProbe,
-							LivenessProbe:  livenessProbe,
+							Args:            args,
+							Image:           instance.Spec.ContainerImage,
+							SecurityContext: getContainerSecurityContext(),
+							Env:             env.MergeEnvs([]corev1.EnvVar{}, envVars),
+							VolumeMounts:    volumeMounts,
+							Resources:       instance.Spec.Resources,
+							ReadinessProbe:  readinessProbe,
+							LivenessProbe:   livenessProbe,
 						},
 					},
 					Volumes: volumes,
@@ -141,8 +152,13 @@ func Deployment(
 		},
 		corev1.LabelHostname,
 	)
-	if instance.Spec.NodeSelector != nil && len(instance.Spec.NodeSelector) > 0 {
-		deployment.Spec.Template.Spec.NodeSelector = instance.Spec.NodeSelector
+	applyScheduling(instance, &deployment.Spec.Template.Spec, labels)
+
+	// the octavia-driver-agent receives the status updates of the provider drivers
+	// via unix sockets in /run/octavia, which is shared with the API container
+	if instance.IsProviderEnabled(octaviav1.ProviderDriverOVN) {
+		deployment.Spec.Template.Spec.Containers = append(
+			deployment.Spec.Template.Spec.Containers, driverAgentContainer(instance, configHash))
 	}
 
 	initContainerDetails := APIDetails{
@@ -155,7 +171,75 @@ func Deployment(
 		UserPasswordSelector: instance.Spec.PasswordSelectors.Service,
 		VolumeMounts:         initVolumeMounts,
 	}
+	if instance.Spec.ApplicationCredential.Enabled {
+		initContainerDetails.ApplicationCredentialSecret = ApplicationCredentialSecretName(instance)
+	}
 	deployment.Spec.Template.Spec.InitContainers = initContainer(initContainerDetails)
 
 	return deployment
 }
+
+// DeploymentStrategy - the rolling update strategy of the API deployment, by default a new pod
+// has to be available before an old one gets removed
+func DeploymentStrategy(instance *octaviav1.OctaviaAPI) appsv1.DeploymentStrategy {
+	maxUnavailable := intstr.FromInt(0)
+	if instance.Spec.MaxUnavailable != nil {
+		maxUnavailable = *instance.Spec.MaxUnavailable
+	}
+	maxSurge := intstr.FromInt(1)
+	if instance.Spec.MaxSurge != nil {
+		maxSurge = *instance.Spec.MaxSurge
+	}
+
+	return appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxUnavailable: &maxUnavailable,
+			MaxSurge:       &maxSurge,
+		},
+	}
+}
+
+// driverAgentContainer - the octavia-driver-agent sidecar container
+func driverAgentContainer(
+	instance *octaviav1.OctaviaAPI,
+	configHash string,
+) corev1.Container {
+	args := []string{"-c"}
+	if instance.Spec.Debug.Service {
+		args = append(args, DebugCommand)
+	} else {
+		args = append(args, DriverAgentCommand)
+	}
+
+	envVars := map[string]env.Setter{}
+	envVars["CONFIG_HASH"] = env.SetValue(configHash)
+
+	return corev1.Container{
+		Name: ServiceName + "-driver-agent",
+		Command: []string{
+			"/bin/bash",
+		},
+		Args:            args,
+		Image:           instance.Spec.ContainerImage,
+		SecurityContext: getContainerSecurityContext(),
+		Env:             env.MergeEnvs([]corev1.EnvVar{}, envVars),
+		VolumeMounts:    getVolumeMounts(),
+		Resources:       instance.Spec.Resources,
+	}
+}
+
+// IsDeploymentReady - returns true if the deployment controller observed the latest spec and all
+// desired replicas are updated and available, with no pods of an older revision left over
+func IsDeploymentReady(depl *appsv1.Deployment) bool {
+	desired := int32(1)
+	if depl.Spec.Replicas != nil {
+		desired = *depl.Spec.Replicas
+	}
+
+	return depl.Status.ObservedGeneration >= depl.Generation &&
+		depl.Status.Replicas == desired &&
+		depl.Status.UpdatedReplicas == desired &&
+		depl.Status.AvailableReplicas == desired &&
+		depl.Status.ReadyReplicas == desired
+}
@@ -0,0 +1,63 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsDeploymentReady(t *testing.T) {
+	replicas := int32(3)
+	ready := appsv1.DeploymentStatus{
+		ObservedGeneration: 2,
+		Replicas:           3,
+		UpdatedReplicas:    3,
+		AvailableReplicas:  3,
+		ReadyReplicas:      3,
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(status *appsv1.DeploymentStatus)
+		ready  bool
+	}{
+		{name: "all replicas ready", mutate: func(status *appsv1.DeploymentStatus) {}, ready: true},
+		{name: "spec not yet observed", mutate: func(status *appsv1.DeploymentStatus) { status.ObservedGeneration = 1 }},
+		{name: "partially available", mutate: func(status *appsv1.DeploymentStatus) { status.AvailableReplicas = 1 }},
+		{name: "rollout in progress", mutate: func(status *appsv1.DeploymentStatus) {
+			status.Replicas = 4
+			status.UpdatedReplicas = 2
+		}},
+		{name: "old pods left over", mutate: func(status *appsv1.DeploymentStatus) { status.Replicas = 4 }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			depl := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+				Status:     ready,
+			}
+			tc.mutate(&depl.Status)
+			if got := IsDeploymentReady(depl); got != tc.ready {
+				t.Errorf("IsDeploymentReady() = %v, want %v", got, tc.ready)
+			}
+		})
+	}
+}
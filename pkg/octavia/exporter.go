@@ -0,0 +1,203 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"fmt"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// ExporterName - name of the exporter deployment, service and service monitor
+	ExporterName = ServiceName + "-exporter"
+	// ExporterCommand - the exporter binary shipped in the operator image
+	ExporterCommand = "/octavia-exporter"
+	// ExporterPortName - name of the metrics port of the exporter
+	ExporterPortName = "metrics"
+)
+
+// ServiceMonitorGVK - the prometheus-operator ServiceMonitor, which is not a dependency of the
+// operator and therefore handled as unstructured object
+var ServiceMonitorGVK = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "ServiceMonitor",
+}
+
+// ExporterLabels - labels of the exporter resources, they must not match the API service selector
+func ExporterLabels() map[string]string {
+	return map[string]string{
+		common.AppSelector: ExporterName,
+	}
+}
+
+// ExporterDeployment - the deployment of the stats exporter polling the Octavia API at octaviaURL
+// using the service user which authenticates at authURL
+func ExporterDeployment(
+	instance *octaviav1.OctaviaAPI,
+	labels map[string]string,
+	authURL string,
+	octaviaURL string,
+) *appsv1.Deployment {
+	replicas := int32(1)
+	port := instance.Spec.Exporter.Port
+
+	envVars := map[string]env.Setter{}
+	envVars["OS_AUTH_URL"] = env.SetValue(authURL)
+	envVars["OS_USERNAME"] = env.SetValue(instance.Spec.ServiceUser)
+	envVars["OS_PROJECT_NAME"] = env.SetValue("service")
+	envVars["OS_PROJECT_DOMAIN_NAME"] = env.SetValue("Default")
+	envVars["OS_USER_DOMAIN_NAME"] = env.SetValue("Default")
+	envVars["OCTAVIA_URL"] = env.SetValue(octaviaURL)
+	envs := env.MergeEnvs([]corev1.EnvVar{}, envVars)
+	envs = append(envs, corev1.EnvVar{
+		Name: "OS_PASSWORD",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: instance.Spec.Secret,
+				},
+				Key: instance.Spec.PasswordSelectors.Service,
+			},
+		},
+	})
+
+	probe := &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/healthz",
+				Port: intstr.FromInt(int(port)),
+			},
+		},
+		TimeoutSeconds: 5,
+		PeriodSeconds:  15,
+	}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ExporterName,
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: ServiceAccount,
+					SecurityContext:    getPodSecurityContext(),
+					Containers: []corev1.Container{
+						{
+							Name:    ExporterName,
+							Command: []string{ExporterCommand},
+							Args: []string{
+								fmt.Sprintf("-listen-address=:%d", port),
+								fmt.Sprintf("-poll-interval=%s", instance.Spec.Exporter.PollInterval.Duration),
+							},
+							Image:           instance.Spec.Exporter.ContainerImage,
+							SecurityContext: getContainerSecurityContext(),
+							Env:             envs,
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          ExporterPortName,
+									ContainerPort: port,
+									Protocol:      corev1.ProtocolTCP,
+								},
+							},
+							ReadinessProbe: probe,
+							LivenessProbe:  probe,
+						},
+					},
+				},
+			},
+		},
+	}
+	applyScheduling(instance, &deployment.Spec.Template.Spec, labels)
+
+	return deployment
+}
+
+// ExporterService - the service exposing the metrics port of the exporter
+func ExporterService(
+	instance *octaviav1.OctaviaAPI,
+	labels map[string]string,
+) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ExporterName,
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       ExporterPortName,
+					Port:       instance.Spec.Exporter.Port,
+					TargetPort: intstr.FromString(ExporterPortName),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+}
+
+// ExporterServiceMonitor - the ServiceMonitor scraping the exporter service
+func ExporterServiceMonitor(
+	instance *octaviav1.OctaviaAPI,
+	labels map[string]string,
+) *unstructured.Unstructured {
+	selector := map[string]interface{}{}
+	for k, v := range labels {
+		selector[k] = v
+	}
+
+	monitor := &unstructured.Unstructured{}
+	monitor.SetGroupVersionKind(ServiceMonitorGVK)
+	monitor.SetName(ExporterName)
+	monitor.SetNamespace(instance.Namespace)
+	monitor.SetLabels(labels)
+	monitor.Object["spec"] = map[string]interface{}{
+		"endpoints": []interface{}{
+			map[string]interface{}{
+				"port": ExporterPortName,
+				"path": "/metrics",
+				// prometheus durations do not support fractions
+				"interval": fmt.Sprintf("%ds", int64(instance.Spec.Exporter.PollInterval.Seconds())),
+			},
+		},
+		"selector": map[string]interface{}{
+			"matchLabels": selector,
+		},
+	}
+
+	return monitor
+}
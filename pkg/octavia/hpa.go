@@ -0,0 +1,98 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultTargetCPUUtilization - CPU utilization target of the HPA in percent if the spec sets
+// neither a CPU utilization nor a request rate target
+const DefaultTargetCPUUtilization int32 = 80
+
+// HorizontalPodAutoscaler - returns the HPA of the API deployment, nil if autoscaling is disabled
+func HorizontalPodAutoscaler(
+	instance *octaviav1.OctaviaAPI,
+	labels map[string]string,
+) *autoscalingv2.HorizontalPodAutoscaler {
+	if !instance.IsAutoscalingEnabled() {
+		return nil
+	}
+	autoscaling := instance.Spec.Autoscaling
+
+	minReplicas := autoscaling.MinReplicas
+	maxReplicas := autoscaling.MaxReplicas
+	if maxReplicas < minReplicas {
+		maxReplicas = minReplicas
+	}
+
+	// scale on the CPU utilization if no target is given
+	targetCPUUtilization := DefaultTargetCPUUtilization
+	if autoscaling.TargetCPUUtilization != nil {
+		targetCPUUtilization = *autoscaling.TargetCPUUtilization
+	}
+
+	metrics := []autoscalingv2.MetricSpec{}
+	if autoscaling.TargetCPUUtilization != nil || autoscaling.TargetRequestsPerSecond == nil {
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: &targetCPUUtilization,
+				},
+			},
+		})
+	}
+	if autoscaling.TargetRequestsPerSecond != nil {
+		targetRequestsPerSecond := autoscaling.TargetRequestsPerSecond.DeepCopy()
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{
+					Name: autoscaling.RequestRateMetric,
+				},
+				Target: autoscalingv2.MetricTarget{
+					Type:         autoscalingv2.AverageValueMetricType,
+					AverageValue: &targetRequestsPerSecond,
+				},
+			},
+		})
+	}
+
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ServiceName,
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       ServiceName,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: maxReplicas,
+			Metrics:     metrics,
+		},
+	}
+}
@@ -0,0 +1,71 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"testing"
+
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestHorizontalPodAutoscaler(t *testing.T) {
+	labels := map[string]string{"service": ServiceName}
+
+	instance := testInstance()
+	if hpa := HorizontalPodAutoscaler(instance, labels); hpa != nil {
+		t.Errorf("expected no HPA with autoscaling disabled, got %v", hpa)
+	}
+
+	requestRate := resource.MustParse("50")
+	targetCPUUtilization := int32(70)
+	instance.Spec.Autoscaling = octaviav1.OctaviaAutoscaling{
+		Enabled:                 true,
+		MinReplicas:             2,
+		MaxReplicas:             5,
+		TargetCPUUtilization:    &targetCPUUtilization,
+		TargetRequestsPerSecond: &requestRate,
+		RequestRateMetric:       "http_requests_per_second",
+	}
+	assertGolden(t, "hpa", HorizontalPodAutoscaler(instance, labels))
+
+	// only the request rate if no CPU target is given
+	instance.Spec.Autoscaling.TargetCPUUtilization = nil
+	hpa := HorizontalPodAutoscaler(instance, labels)
+	if len(hpa.Spec.Metrics) != 1 || hpa.Spec.Metrics[0].Type != autoscalingv2.PodsMetricSourceType {
+		t.Errorf("expected only the request rate metric, got %v", hpa.Spec.Metrics)
+	}
+
+	// the default CPU target without any target
+	instance.Spec.Autoscaling.TargetRequestsPerSecond = nil
+	hpa = HorizontalPodAutoscaler(instance, labels)
+	if len(hpa.Spec.Metrics) != 1 || *hpa.Spec.Metrics[0].Resource.Target.AverageUtilization != DefaultTargetCPUUtilization {
+		t.Errorf("expected the default CPU metric, got %v", hpa.Spec.Metrics)
+	}
+
+	// the max replicas get raised to the min replicas
+	instance.Spec.Autoscaling.MaxReplicas = 1
+	if hpa := HorizontalPodAutoscaler(instance, labels); hpa.Spec.MaxReplicas != 2 {
+		t.Errorf("MaxReplicas = %d, want 2", hpa.Spec.MaxReplicas)
+	}
+
+	instance.Spec.Maintenance = true
+	if hpa := HorizontalPodAutoscaler(instance, labels); hpa != nil {
+		t.Errorf("expected no HPA in maintenance mode, got %v", hpa)
+	}
+}
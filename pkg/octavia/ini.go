@@ -0,0 +1,163 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"strings"
+)
+
+// defaultSection - name of the section holding the options before the first section header
+const defaultSection = "DEFAULT"
+
+// IniFile - minimal INI file representation which implements the crudini --merge and --set
+// semantics used by the init container. Comments and formatting of the file are preserved.
+type IniFile struct {
+	sections []*iniSection
+}
+
+type iniSection struct {
+	// name is empty for the lines before the first section header
+	name  string
+	lines []iniLine
+}
+
+type iniLine struct {
+	// key is empty for comments and blank lines
+	key   string
+	value string
+	raw   string
+}
+
+// ParseIni - parses the INI formatted data
+func ParseIni(data string) *IniFile {
+	current := &iniSection{}
+	f := &IniFile{sections: []*iniSection{current}}
+
+	data = strings.TrimRight(data, "\n")
+	if data == "" {
+		return f
+	}
+
+	for _, raw := range strings.Split(data, "\n") {
+		line := strings.TrimSpace(raw)
+		switch {
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			current = &iniSection{name: strings.TrimSpace(line[1 : len(line)-1])}
+			f.sections = append(f.sections, current)
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			current.lines = append(current.lines, iniLine{raw: raw})
+		default:
+			key, value, _ := strings.Cut(line, "=")
+			current.lines = append(current.lines, iniLine{
+				key:   strings.TrimSpace(key),
+				value: strings.TrimSpace(value),
+				raw:   raw,
+			})
+		}
+	}
+
+	return f
+}
+
+// getSection - returns the section with name, the options before the first section
+// header are part of the DEFAULT section
+func (f *IniFile) getSection(name string, create bool) *iniSection {
+	for _, s := range f.sections {
+		if s.name == name {
+			return s
+		}
+	}
+	if name == defaultSection && !create {
+		return f.sections[0]
+	}
+	if !create {
+		return nil
+	}
+
+	s := &iniSection{name: name}
+	f.sections = append(f.sections, s)
+	return s
+}
+
+// Get - returns the value of the option key in section
+func (f *IniFile) Get(section string, key string) (string, bool) {
+	s := f.getSection(section, false)
+	if s == nil {
+		return "", false
+	}
+	for _, l := range s.lines {
+		if l.key == key {
+			return l.value, true
+		}
+	}
+	return "", false
+}
+
+// Set - sets the option key in section to value like crudini --set. The section gets
+// created if it does not exist.
+func (f *IniFile) Set(section string, key string, value string) {
+	s := f.getSection(section, false)
+	if s == nil {
+		s = f.getSection(section, true)
+	}
+
+	line := iniLine{key: key, value: value, raw: key + "=" + value}
+	for i, l := range s.lines {
+		if l.key == key {
+			s.lines[i] = line
+			return
+		}
+	}
+
+	// add the option after the last option of the section to keep trailing comments
+	idx := len(s.lines)
+	for idx > 0 && s.lines[idx-1].key == "" {
+		idx--
+	}
+	s.lines = append(s.lines[:idx], append([]iniLine{line}, s.lines[idx:]...)...)
+}
+
+// Merge - merges all sections and options of other into f like crudini --merge
+func (f *IniFile) Merge(other *IniFile) {
+	for _, s := range other.sections {
+		name := s.name
+		if name == "" {
+			name = defaultSection
+		}
+		if name != defaultSection && f.getSection(name, false) == nil {
+			f.getSection(name, true)
+		}
+		for _, l := range s.lines {
+			if l.key != "" {
+				f.Set(name, l.key, l.value)
+			}
+		}
+	}
+}
+
+// String - returns the INI formatted data
+func (f *IniFile) String() string {
+	var b strings.Builder
+	for _, s := range f.sections {
+		if s.name != "" {
+			b.WriteString("[" + s.name + "]\n")
+		}
+		for _, l := range s.lines {
+			b.WriteString(l.raw + "\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,93 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"testing"
+)
+
+func TestIniMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		merge    string
+		expected string
+	}{
+		{
+			name:     "override existing option",
+			base:     "[DEFAULT]\ndebug=False\n[api_settings]\nbind_port=9876\n",
+			merge:    "[DEFAULT]\ndebug = True\n",
+			expected: "[DEFAULT]\ndebug=True\n[api_settings]\nbind_port=9876\n",
+		},
+		{
+			name:     "add option and keep comments",
+			base:     "[api_settings]\n# the port\nbind_port=9876\n\n[database]\n",
+			merge:    "[api_settings]\nbind_host=::\n",
+			expected: "[api_settings]\n# the port\nbind_port=9876\nbind_host=::\n\n[database]\n",
+		},
+		{
+			name:     "add section",
+			base:     "[DEFAULT]\ndebug=True\n",
+			merge:    "[ovn]\novn_nb_connection=tcp:nb:6641\n[driver_agent]\n",
+			expected: "[DEFAULT]\ndebug=True\n[ovn]\novn_nb_connection=tcp:nb:6641\n[driver_agent]\n",
+		},
+		{
+			name:     "options before the first section are DEFAULT options",
+			base:     "debug=False\n[database]\n",
+			merge:    "[DEFAULT]\ndebug=True\n",
+			expected: "debug=True\n[database]\n",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			f := ParseIni(tc.base)
+			f.Merge(ParseIni(tc.merge))
+			if actual := f.String(); actual != tc.expected {
+				t.Errorf("expected:\n%s\nactual:\n%s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMergeConfigDir(t *testing.T) {
+	merged := map[string]string{
+		"octavia.conf": "[DEFAULT]\ndebug=False\n",
+	}
+	MergeConfigDir(merged, map[string]string{
+		"octavia.conf": "[DEFAULT]\ndebug=True\n",
+		"custom.conf":  "[DEFAULT]\nverbose=True\n",
+		"policy.json":  "{}",
+	})
+
+	expected := map[string]string{
+		"octavia.conf": "[DEFAULT]\ndebug=True\n",
+		"custom.conf":  "[DEFAULT]\nverbose=True\n",
+		"policy.json":  "{}",
+	}
+	for name, data := range expected {
+		if merged[name] != data {
+			t.Errorf("%s: expected %q, got %q", name, data, merged[name])
+		}
+	}
+
+	conf := ParseIni(merged["octavia.conf"])
+	conf.Set("database", "connection", DatabaseConnection("octavia", "pw", "openstack", DatabaseName))
+	if v, _ := conf.Get("database", "connection"); v != "mysql+pymysql://octavia:pw@openstack/octavia" {
+		t.Errorf("unexpected database connection %s", v)
+	}
+}
@@ -31,6 +31,9 @@ type APIDetails struct {
 	DBPasswordSelector   string
 	UserPasswordSelector string
 	VolumeMounts         []corev1.VolumeMount
+	// ApplicationCredentialSecret - secret holding the application credential of the service
+	// user, if enabled
+	ApplicationCredentialSecret string
 }
 
 const (
@@ -40,8 +43,6 @@ const (
 
 // initContainer - init container for octavia api pods
 func initContainer(init APIDetails) []corev1.Container {
-	runAsUser := int64(0)
-
 	args := []string{
 		"-c",
 		InitContainerCommand,
@@ -76,21 +77,35 @@ func initContainer(init APIDetails) []corev1.Container {
 			},
 		},
 	}
+	for _, key := range []string{ApplicationCredentialIDKey, ApplicationCredentialSecretKey} {
+		if init.ApplicationCredentialSecret == "" {
+			break
+		}
+		envs = append(envs, corev1.EnvVar{
+			Name: key,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: init.ApplicationCredentialSecret,
+					},
+					Key: key,
+				},
+			},
+		})
+	}
 	envs = env.MergeEnvs(envs, envVars)
 
 	return []corev1.Container{
 		{
-			Name:  "init",
-			Image: init.ContainerImage,
-			SecurityContext: &corev1.SecurityContext{
-				RunAsUser: &runAsUser,
-			},
+			Name:            "init",
+			Image:           init.ContainerImage,
+			SecurityContext: getContainerSecurityContext(),
 			Command: []string{
 				"/bin/bash",
 			},
 			Args:         args,
 			Env:          envs,
-			VolumeMounts: getInitVolumeMounts(),
+			VolumeMounts: init.VolumeMounts,
 		},
 	}
 }
@@ -0,0 +1,93 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetClusterIPFamilies - returns the IP family of the kubernetes API service in the default
+// namespace. The service is single-stack, its family is the primary family of the cluster which
+// services without IPFamilies get. Returns IPv4 if the service does not report its families.
+func GetClusterIPFamilies(
+	ctx context.Context,
+	h *helper.Helper,
+) ([]corev1.IPFamily, error) {
+	// use kclient to not start a cache for the services of the default namespace
+	svc, err := h.GetKClient().CoreV1().Services(metav1.NamespaceDefault).Get(ctx, "kubernetes", metav1.GetOptions{})
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err != nil || len(svc.Spec.IPFamilies) == 0 {
+		return []corev1.IPFamily{corev1.IPv4Protocol}, nil
+	}
+	return svc.Spec.IPFamilies, nil
+}
+
+// GetEndpointIPFamilies - returns the IP families the endpoint services of the instance ask for.
+// Services without IPFamilies get the primary family of the clusterFamilies, and a dual-stack
+// IPFamilyPolicy asks for IPv6 in addition.
+func GetEndpointIPFamilies(instance *octaviav1.OctaviaAPI, clusterFamilies []corev1.IPFamily) []corev1.IPFamily {
+	requested := map[corev1.IPFamily]bool{}
+	for _, endpointType := range Endpoints {
+		override := instance.GetServiceOverride(endpointType)
+		if len(override.IPFamilies) == 0 && len(clusterFamilies) > 0 {
+			requested[clusterFamilies[0]] = true
+		}
+		for _, family := range override.IPFamilies {
+			requested[family] = true
+		}
+		if override.IPFamilyPolicy != nil && *override.IPFamilyPolicy != corev1.IPFamilyPolicySingleStack {
+			requested[corev1.IPv6Protocol] = true
+		}
+	}
+
+	families := []corev1.IPFamily{}
+	for _, family := range []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol} {
+		if requested[family] {
+			families = append(families, family)
+		}
+	}
+	return families
+}
+
+// GetBindHost - returns the wildcard address the API binds to. If any endpoint asks for IPv6
+// this is ::, which also accepts IPv4 connections.
+func GetBindHost(families []corev1.IPFamily) string {
+	for _, family := range families {
+		if family == corev1.IPv6Protocol {
+			return "::"
+		}
+	}
+	return "0.0.0.0"
+}
+
+// FormatHost - returns the host for the use in URLs, IPv6 addresses get enclosed in brackets
+func FormatHost(host string) string {
+	if strings.Contains(host, ":") && net.ParseIP(host) != nil {
+		return "[" + host + "]"
+	}
+	return host
+}
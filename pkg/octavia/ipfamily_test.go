@@ -0,0 +1,89 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGetBindHost(t *testing.T) {
+	for _, tc := range []struct {
+		families []corev1.IPFamily
+		expected string
+	}{
+		{families: nil, expected: "0.0.0.0"},
+		{families: []corev1.IPFamily{corev1.IPv4Protocol}, expected: "0.0.0.0"},
+		{families: []corev1.IPFamily{corev1.IPv6Protocol}, expected: "::"},
+		{families: []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}, expected: "::"},
+	} {
+		if actual := GetBindHost(tc.families); actual != tc.expected {
+			t.Errorf("GetBindHost(%v) = %s, expected %s", tc.families, actual, tc.expected)
+		}
+	}
+}
+
+func TestGetEndpointIPFamilies(t *testing.T) {
+	ipv4 := []corev1.IPFamily{corev1.IPv4Protocol}
+	ipv6 := []corev1.IPFamily{corev1.IPv6Protocol}
+	dualStack := []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}
+	singleStack := corev1.IPFamilyPolicySingleStack
+	preferDualStack := corev1.IPFamilyPolicyPreferDualStack
+
+	for name, tc := range map[string]struct {
+		clusterFamilies []corev1.IPFamily
+		override        octaviav1.OctaviaServiceOverride
+		expected        []corev1.IPFamily
+	}{
+		"ipv4 cluster":           {clusterFamilies: ipv4, expected: ipv4},
+		"ipv6 cluster":           {clusterFamilies: ipv6, expected: ipv6},
+		"ipv6 endpoint":          {clusterFamilies: ipv4, override: octaviav1.OctaviaServiceOverride{IPFamilies: ipv6}, expected: dualStack},
+		"dual-stack endpoint":    {clusterFamilies: ipv4, override: octaviav1.OctaviaServiceOverride{IPFamilyPolicy: &preferDualStack}, expected: dualStack},
+		"single-stack endpoint":  {clusterFamilies: ipv4, override: octaviav1.OctaviaServiceOverride{IPFamilyPolicy: &singleStack}, expected: ipv4},
+		"ipv4 endpoints on ipv6": {clusterFamilies: ipv6, override: octaviav1.OctaviaServiceOverride{IPFamilies: ipv4}, expected: dualStack},
+	} {
+		instance := testInstance()
+		instance.Spec.Override.Service = map[endpoint.Endpoint]octaviav1.OctaviaServiceOverride{
+			endpoint.EndpointInternal: tc.override,
+		}
+		if actual := GetEndpointIPFamilies(instance, tc.clusterFamilies); !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", name, tc.expected, actual)
+		}
+	}
+}
+
+func TestFormatHost(t *testing.T) {
+	for host, expected := range map[string]string{
+		"openstack.openstack.svc": "openstack.openstack.svc",
+		"172.17.0.80":             "172.17.0.80",
+		"fd00:bbbb::80":           "[fd00:bbbb::80]",
+		"[fd00:bbbb::80]":         "[fd00:bbbb::80]",
+	} {
+		if actual := FormatHost(host); actual != expected {
+			t.Errorf("FormatHost(%s) = %s, expected %s", host, actual, expected)
+		}
+	}
+
+	connection := DatabaseConnection("octavia", "pw", "fd00:bbbb::3", DatabaseName)
+	if connection != "mysql+pymysql://octavia:pw@[fd00:bbbb::3]/octavia" {
+		t.Errorf("unexpected database connection: %s", connection)
+	}
+}
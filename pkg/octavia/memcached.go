@@ -0,0 +1,91 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"context"
+	"strings"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// MemcachedGVK - the Memcached kind of the infra-operator. It is accessed as unstructured
+// object to not depend on the infra-operator API module.
+var MemcachedGVK = schema.GroupVersionKind{
+	Group:   "memcached.openstack.org",
+	Version: "v1beta1",
+	Kind:    "Memcached",
+}
+
+// GetMemcachedServers - returns the servers of the Memcached instance with name in the namespace.
+// Returns nil if the instance does not exist, is not ready or did not yet report its servers.
+func GetMemcachedServers(
+	ctx context.Context,
+	h *helper.Helper,
+	namespace string,
+	name string,
+) ([]string, error) {
+	memcached := &unstructured.Unstructured{}
+	memcached.SetGroupVersionKind(MemcachedGVK)
+
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, memcached)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if !isUnstructuredReady(memcached) {
+		return nil, nil
+	}
+
+	servers, _, err := unstructured.NestedStringSlice(memcached.Object, "status", "serverList")
+	if err != nil {
+		return nil, err
+	}
+	return servers, nil
+}
+
+// MemcachedServers - returns the value for the memcached_servers config option
+func MemcachedServers(servers []string) string {
+	return strings.Join(servers, ",")
+}
+
+// isUnstructuredReady - returns true if the Ready condition of the object is True
+func isUnstructuredReady(obj *unstructured.Unstructured) bool {
+	conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return false
+	}
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if cond["type"] == string(condition.ReadyCondition) {
+			return cond["status"] == string(corev1.ConditionTrue)
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestIsUnstructuredReady(t *testing.T) {
+	memcached := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	if isUnstructuredReady(memcached) {
+		t.Errorf("expected an object without status not to be ready")
+	}
+
+	for status, expected := range map[string]bool{"True": true, "False": false, "Unknown": false} {
+		memcached.Object["status"] = map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "DeploymentReady", "status": "True"},
+				map[string]interface{}{"type": "Ready", "status": status},
+			},
+		}
+		if actual := isUnstructuredReady(memcached); actual != expected {
+			t.Errorf("Ready=%s: expected ready %v, got %v", status, expected, actual)
+		}
+	}
+
+	if servers := MemcachedServers([]string{"memcached-0.memcached:11211", "memcached-1.memcached:11211"}); servers != "memcached-0.memcached:11211,memcached-1.memcached:11211" {
+		t.Errorf("unexpected memcached_servers: %s", servers)
+	}
+}
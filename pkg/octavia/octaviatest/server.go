@@ -0,0 +1,619 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package octaviatest provides an in-memory Octavia v2 API, including the keystone token and
+// application credential endpoints, to test the clients of the operator.
+package octaviatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FlavorProfile - flavor profile stored in the server
+type FlavorProfile struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	ProviderName string `json:"provider_name"`
+	FlavorData   string `json:"flavor_data"`
+}
+
+// Flavor - flavor stored in the server
+type Flavor struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	FlavorProfileID string `json:"flavor_profile_id"`
+	Enabled         bool   `json:"enabled"`
+}
+
+// LoadBalancer - load balancer stored in the server
+type LoadBalancer struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	ProjectID          string `json:"project_id"`
+	Provider           string `json:"provider"`
+	FlavorID           string `json:"flavor_id"`
+	ProvisioningStatus string `json:"provisioning_status"`
+	OperatingStatus    string `json:"operating_status"`
+}
+
+// Listener - listener stored in the server
+type Listener struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	ProjectID      string `json:"project_id"`
+	Protocol       string `json:"protocol"`
+	ProtocolPort   int    `json:"protocol_port"`
+	LoadBalancerID string `json:"-"`
+}
+
+// Amphora - amphora stored in the server
+type Amphora struct {
+	ID             string `json:"id"`
+	LoadBalancerID string `json:"loadbalancer_id"`
+	ComputeID      string `json:"compute_id"`
+	Status         string `json:"status"`
+	Role           string `json:"role"`
+}
+
+// Stats - traffic statistics of a load balancer or listener
+type Stats struct {
+	BytesIn           int64 `json:"bytes_in"`
+	BytesOut          int64 `json:"bytes_out"`
+	ActiveConnections int64 `json:"active_connections"`
+	TotalConnections  int64 `json:"total_connections"`
+	RequestErrors     int64 `json:"request_errors"`
+}
+
+// ApplicationCredential - keystone application credential stored in the server
+type ApplicationCredential struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Secret string `json:"secret,omitempty"`
+	UserID string `json:"user_id"`
+}
+
+// Server - Octavia API at URL, and keystone at IdentityURL which issues a token for any user
+type Server struct {
+	*httptest.Server
+
+	mu             sync.Mutex
+	nextID         int
+	flavorProfiles map[string]FlavorProfile
+	flavors        map[string]Flavor
+	loadBalancers  map[string]LoadBalancer
+	listeners      map[string]Listener
+	amphorae       map[string]Amphora
+	stats          map[string]Stats
+	appCreds       map[string]ApplicationCredential
+	requests       []string
+	pageSize       int
+}
+
+// NewServer - starts a server without any resources, which has to be closed by the caller
+func NewServer() *Server {
+	s := &Server{
+		flavorProfiles: map[string]FlavorProfile{},
+		flavors:        map[string]Flavor{},
+		loadBalancers:  map[string]LoadBalancer{},
+		listeners:      map[string]Listener{},
+		amphorae:       map[string]Amphora{},
+		stats:          map[string]Stats{},
+		appCreds:       map[string]ApplicationCredential{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// IdentityURL - the keystone v3 endpoint of the server
+func (s *Server) IdentityURL() string {
+	return s.URL + "/identity/v3"
+}
+
+// Requests - the method and path of the Octavia API requests the server got, e.g. "DELETE /v2/lbaas/flavors/1"
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.requests...)
+}
+
+// FlavorProfiles - the flavor profiles sorted by ID
+func (s *Server) FlavorProfiles() []FlavorProfile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := []FlavorProfile{}
+	for _, fp := range s.flavorProfiles {
+		result = append(result, fp)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// Flavors - the flavors sorted by ID
+func (s *Server) Flavors() []Flavor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := []Flavor{}
+	for _, f := range s.flavors {
+		result = append(result, f)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// ApplicationCredentials - the application credentials sorted by ID
+func (s *Server) ApplicationCredentials() []ApplicationCredential {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := []ApplicationCredential{}
+	for _, appCred := range s.appCreds {
+		result = append(result, appCred)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// SetFlavorProfile - adds or replaces the flavor profile, e.g. to change it out of band.
+// An empty ID gets generated. Returns the ID.
+func (s *Server) SetFlavorProfile(fp FlavorProfile) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if fp.ID == "" {
+		fp.ID = s.newID()
+	}
+	s.flavorProfiles[fp.ID] = fp
+	return fp.ID
+}
+
+// SetFlavor - adds or replaces the flavor, e.g. to change it out of band. An empty ID gets
+// generated. Returns the ID.
+func (s *Server) SetFlavor(f Flavor) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f.ID == "" {
+		f.ID = s.newID()
+	}
+	s.flavors[f.ID] = f
+	return f.ID
+}
+
+// DeleteFlavor - removes the flavor with id, e.g. to delete it out of band
+func (s *Server) DeleteFlavor(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.flavors, id)
+}
+
+// SetLoadBalancer - adds or replaces the load balancer. An empty ID gets generated. Returns the ID.
+func (s *Server) SetLoadBalancer(lb LoadBalancer) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if lb.ID == "" {
+		lb.ID = s.newID()
+	}
+	s.loadBalancers[lb.ID] = lb
+	return lb.ID
+}
+
+// DeleteLoadBalancer - removes the load balancer with id
+func (s *Server) DeleteLoadBalancer(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.loadBalancers, id)
+}
+
+// SetListener - adds or replaces the listener. An empty ID gets generated. Returns the ID.
+func (s *Server) SetListener(l Listener) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if l.ID == "" {
+		l.ID = s.newID()
+	}
+	s.listeners[l.ID] = l
+	return l.ID
+}
+
+// SetAmphora - adds or replaces the amphora. An empty ID gets generated. Returns the ID.
+func (s *Server) SetAmphora(a Amphora) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a.ID == "" {
+		a.ID = s.newID()
+	}
+	s.amphorae[a.ID] = a
+	return a.ID
+}
+
+// SetPageSize - limits the number of items of a list response like pagination_max_limit of
+// Octavia, the next page is linked in the <resource>_links of the response. 0 returns all items.
+func (s *Server) SetPageSize(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pageSize = size
+}
+
+// SetStats - sets the statistics returned for the load balancer with id
+func (s *Server) SetStats(id string, stats Stats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats[id] = stats
+}
+
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("%08d-0000-0000-0000-000000000000", s.nextID)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost && r.URL.Path == "/identity/v3/auth/tokens" {
+		s.issueToken(w)
+		return
+	}
+	if r.Header.Get("X-Auth-Token") == "" {
+		writeError(w, http.StatusUnauthorized)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+	if strings.HasPrefix(r.URL.Path, "/identity/v3/users/") {
+		s.handleUsers(w, r, strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/identity/v3/users/"), "/"), "/"))
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/v2/"), "/"), "/")
+	switch {
+	case len(parts) >= 2 && parts[0] == "lbaas" && parts[1] == "flavorprofiles":
+		s.handleFlavorProfiles(w, r, parts[2:])
+	case len(parts) >= 2 && parts[0] == "lbaas" && parts[1] == "flavors":
+		s.handleFlavors(w, r, parts[2:])
+	case len(parts) >= 2 && parts[0] == "lbaas" && parts[1] == "loadbalancers":
+		s.handleLoadBalancers(w, r, parts[2:])
+	case len(parts) == 2 && parts[0] == "lbaas" && parts[1] == "listeners" && r.Method == http.MethodGet:
+		result := []interface{}{}
+		for _, l := range s.listeners {
+			result = append(result, listenerResponse(l))
+		}
+		s.writeList(w, r, "listeners", result)
+	case len(parts) == 2 && parts[0] == "octavia" && parts[1] == "amphorae" && r.Method == http.MethodGet:
+		result := []interface{}{}
+		for _, a := range s.amphorae {
+			result = append(result, a)
+		}
+		s.writeList(w, r, "amphorae", result)
+	default:
+		writeError(w, http.StatusNotFound)
+	}
+}
+
+// issueToken - returns a token with a catalog only holding the keystone endpoint
+func (s *Server) issueToken(w http.ResponseWriter) {
+	token := map[string]interface{}{
+		"token": map[string]interface{}{
+			"expires_at": time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+			"user":       map[string]interface{}{"id": "admin", "name": "admin"},
+			"catalog": []interface{}{
+				map[string]interface{}{
+					"type": "identity",
+					"name": "keystone",
+					"endpoints": []interface{}{
+						map[string]interface{}{
+							"id":        "identity-public",
+							"interface": "public",
+							"region":    "regionOne",
+							"region_id": "regionOne",
+							"url":       s.IdentityURL(),
+						},
+					},
+				},
+			},
+		},
+	}
+	w.Header().Set("X-Subject-Token", "token")
+	writeJSON(w, http.StatusCreated, token)
+}
+
+// handleUsers - the application credentials of the users, path starts with the user ID
+func (s *Server) handleUsers(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) < 2 || path[1] != "application_credentials" {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	userID := path[0]
+
+	switch {
+	case len(path) == 2 && r.Method == http.MethodPost:
+		req := struct {
+			ApplicationCredential ApplicationCredential `json:"application_credential"`
+		}{}
+		if !readJSON(w, r, &req) {
+			return
+		}
+		appCred := req.ApplicationCredential
+		appCred.ID = s.newID()
+		appCred.UserID = userID
+		appCred.Secret = "secret-" + appCred.ID
+		s.appCreds[appCred.ID] = appCred
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"application_credential": appCred})
+	case len(path) == 3 && r.Method == http.MethodDelete:
+		appCred, found := s.appCreds[path[2]]
+		if !found || appCred.UserID != userID {
+			writeError(w, http.StatusNotFound)
+			return
+		}
+		delete(s.appCreds, appCred.ID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleFlavorProfiles(w http.ResponseWriter, r *http.Request, path []string) {
+	switch {
+	case len(path) == 0 && r.Method == http.MethodGet:
+		result := []interface{}{}
+		for _, fp := range s.flavorProfiles {
+			result = append(result, fp)
+		}
+		s.writeList(w, r, "flavorprofiles", result)
+	case len(path) == 0 && r.Method == http.MethodPost:
+		req := struct {
+			FlavorProfile FlavorProfile `json:"flavorprofile"`
+		}{}
+		if !readJSON(w, r, &req) {
+			return
+		}
+		req.FlavorProfile.ID = s.newID()
+		s.flavorProfiles[req.FlavorProfile.ID] = req.FlavorProfile
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"flavorprofile": req.FlavorProfile})
+	case len(path) == 1:
+		fp, found := s.flavorProfiles[path[0]]
+		if !found {
+			writeError(w, http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]interface{}{"flavorprofile": fp})
+		case http.MethodPut:
+			req := struct {
+				FlavorProfile FlavorProfile `json:"flavorprofile"`
+			}{}
+			if !readJSON(w, r, &req) {
+				return
+			}
+			req.FlavorProfile.ID = fp.ID
+			s.flavorProfiles[fp.ID] = req.FlavorProfile
+			writeJSON(w, http.StatusOK, map[string]interface{}{"flavorprofile": req.FlavorProfile})
+		case http.MethodDelete:
+			// like Octavia, a flavor profile in use by a flavor can not be deleted
+			for _, f := range s.flavors {
+				if f.FlavorProfileID == fp.ID {
+					writeError(w, http.StatusConflict)
+					return
+				}
+			}
+			delete(s.flavorProfiles, fp.ID)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeError(w, http.StatusMethodNotAllowed)
+		}
+	default:
+		writeError(w, http.StatusNotFound)
+	}
+}
+
+func (s *Server) handleFlavors(w http.ResponseWriter, r *http.Request, path []string) {
+	switch {
+	case len(path) == 0 && r.Method == http.MethodGet:
+		result := []interface{}{}
+		for _, f := range s.flavors {
+			result = append(result, f)
+		}
+		s.writeList(w, r, "flavors", result)
+	case len(path) == 0 && r.Method == http.MethodPost:
+		req := struct {
+			Flavor Flavor `json:"flavor"`
+		}{}
+		if !readJSON(w, r, &req) {
+			return
+		}
+		if _, found := s.flavorProfiles[req.Flavor.FlavorProfileID]; !found {
+			writeError(w, http.StatusBadRequest)
+			return
+		}
+		req.Flavor.ID = s.newID()
+		s.flavors[req.Flavor.ID] = req.Flavor
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"flavor": req.Flavor})
+	case len(path) == 1:
+		f, found := s.flavors[path[0]]
+		if !found {
+			writeError(w, http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]interface{}{"flavor": f})
+		case http.MethodPut:
+			// only name, description and enabled of a flavor can be updated
+			req := struct {
+				Flavor map[string]interface{} `json:"flavor"`
+			}{}
+			if !readJSON(w, r, &req) {
+				return
+			}
+			for key, value := range req.Flavor {
+				switch key {
+				case "name":
+					f.Name, _ = value.(string)
+				case "description":
+					f.Description, _ = value.(string)
+				case "enabled":
+					f.Enabled, _ = value.(bool)
+				default:
+					writeError(w, http.StatusBadRequest)
+					return
+				}
+			}
+			s.flavors[f.ID] = f
+			writeJSON(w, http.StatusOK, map[string]interface{}{"flavor": f})
+		case http.MethodDelete:
+			delete(s.flavors, f.ID)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeError(w, http.StatusMethodNotAllowed)
+		}
+	default:
+		writeError(w, http.StatusNotFound)
+	}
+}
+
+func (s *Server) handleLoadBalancers(w http.ResponseWriter, r *http.Request, path []string) {
+	switch {
+	case len(path) == 0 && r.Method == http.MethodGet:
+		result := []interface{}{}
+		for _, lb := range s.loadBalancers {
+			result = append(result, lb)
+		}
+		s.writeList(w, r, "loadbalancers", result)
+	case len(path) == 2 && path[1] == "stats" && r.Method == http.MethodGet:
+		if _, found := s.loadBalancers[path[0]]; !found {
+			writeError(w, http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"stats": s.stats[path[0]]})
+	default:
+		writeError(w, http.StatusNotFound)
+	}
+}
+
+// listenerResponse - the listener like Octavia returns it, with the IDs of its load balancers
+func listenerResponse(l Listener) map[string]interface{} {
+	return map[string]interface{}{
+		"id":            l.ID,
+		"name":          l.Name,
+		"project_id":    l.ProjectID,
+		"protocol":      l.Protocol,
+		"protocol_port": l.ProtocolPort,
+		"loadbalancers": []map[string]string{{"id": l.LoadBalancerID}},
+	}
+}
+
+// writeList - writes the items matching the filters of the request sorted by ID. Like Octavia
+// at most limit items after the marker are returned, and the next page gets linked in the
+// <resource>_links.
+func (s *Server) writeList(w http.ResponseWriter, r *http.Request, resource string, items []interface{}) {
+	query := r.URL.Query()
+	limit := s.pageSize
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil && l > 0 && (limit == 0 || l < limit) {
+		limit = l
+	}
+	marker := query.Get("marker")
+
+	filtered := []map[string]interface{}{}
+	for _, item := range items {
+		fields := toFields(item)
+		if matches(query, fields) {
+			filtered = append(filtered, fields)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		return fmt.Sprint(filtered[i]["id"]) < fmt.Sprint(filtered[j]["id"])
+	})
+
+	result := []interface{}{}
+	links := []interface{}{}
+	for _, fields := range filtered {
+		if marker != "" && fmt.Sprint(fields["id"]) <= marker {
+			continue
+		}
+		if limit > 0 && len(result) == limit {
+			next := url.Values{}
+			for key, values := range query {
+				next[key] = values
+			}
+			next.Set("limit", strconv.Itoa(limit))
+			next.Set("marker", fmt.Sprint(result[len(result)-1].(map[string]interface{})["id"]))
+			links = append(links, map[string]string{
+				"rel":  "next",
+				"href": s.URL + r.URL.Path + "?" + next.Encode(),
+			})
+			break
+		}
+		result = append(result, fields)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		resource:            result,
+		resource + "_links": links,
+	})
+}
+
+// toFields - returns the JSON fields of obj
+func toFields(obj interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
+
+// matches - returns true if the JSON fields match the filters of the query, the pagination
+// parameters are no filters
+func matches(query url.Values, fields map[string]interface{}) bool {
+	for key, values := range query {
+		if key == "limit" || key == "marker" || key == "page_reverse" {
+			continue
+		}
+		if fmt.Sprint(fields[key]) != values[0] {
+			return false
+		}
+	}
+	return true
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, obj interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(obj); err != nil {
+		writeError(w, http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, code int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(obj)
+}
+
+func writeError(w http.ResponseWriter, code int) {
+	writeJSON(w, code, map[string]interface{}{
+		"faultcode":   http.StatusText(code),
+		"faultstring": http.StatusText(code),
+	})
+}
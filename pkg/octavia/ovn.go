@@ -0,0 +1,93 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// OVNDBTypeNB - northbound OVN DB
+	OVNDBTypeNB = "NB"
+	// OVNDBTypeSB - southbound OVN DB
+	OVNDBTypeSB = "SB"
+)
+
+// OVNDBClusterGVK - the OVNDBCluster kind of the ovn-operator. It is accessed as unstructured
+// object to not depend on the ovn-operator API module.
+var OVNDBClusterGVK = schema.GroupVersionKind{
+	Group:   "ovn.openstack.org",
+	Version: "v1beta1",
+	Kind:    "OVNDBCluster",
+}
+
+// providerDriverDescriptions - descriptions of the provider drivers as shown by the Octavia API
+var providerDriverDescriptions = map[octaviav1.ProviderDriver]string{
+	octaviav1.ProviderDriverAmphora: "The Octavia Amphora driver.",
+	octaviav1.ProviderDriverOVN:     "Octavia OVN driver.",
+}
+
+// GetEnabledProviderDrivers - returns the value for the enabled_provider_drivers config option
+func GetEnabledProviderDrivers(providers []octaviav1.ProviderDriver) string {
+	drivers := []string{}
+	for _, p := range providers {
+		drivers = append(drivers, fmt.Sprintf("%s: %s", p, providerDriverDescriptions[p]))
+	}
+	return strings.Join(drivers, ",")
+}
+
+// GetOVNDBConnection - returns the connection string of the OVN DB cluster of dbType (NB or SB)
+// in the namespace. An empty string is returned if there is no OVNDBCluster of the type yet,
+// or it did not yet report its DB address.
+func GetOVNDBConnection(
+	ctx context.Context,
+	h *helper.Helper,
+	namespace string,
+	dbType string,
+) (string, error) {
+	clusters := &unstructured.UnstructuredList{}
+	clusters.SetGroupVersionKind(OVNDBClusterGVK.GroupVersion().WithKind(OVNDBClusterGVK.Kind + "List"))
+
+	err := h.GetClient().List(ctx, clusters, client.InNamespace(namespace))
+	if err != nil {
+		return "", err
+	}
+
+	for _, cluster := range clusters.Items {
+		t, _, err := unstructured.NestedString(cluster.Object, "spec", "dbType")
+		if err != nil || !strings.EqualFold(t, dbType) {
+			continue
+		}
+		address, _, err := unstructured.NestedString(cluster.Object, "status", "dbAddress")
+		if err != nil {
+			return "", err
+		}
+		if address != "" {
+			return address, nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,53 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// PodDisruptionBudget - returns the PDB of the API pods, which allows to evict one pod at a time.
+// The PDB allows one unavailable pod instead of requiring a number of available ones, which
+// would fall behind the replicas while the HPA scales the deployment.
+// Returns nil if less than two replicas are configured without autoscaling, the PDB would
+// not protect anything then.
+func PodDisruptionBudget(
+	instance *octaviav1.OctaviaAPI,
+	labels map[string]string,
+) *policyv1.PodDisruptionBudget {
+	if instance.IsMaintenance() || (!instance.IsAutoscalingEnabled() && instance.GetReplicas() < 2) {
+		return nil
+	}
+	maxUnavailable := intstr.FromInt(1)
+
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ServiceName,
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+		},
+	}
+}
@@ -0,0 +1,46 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"testing"
+
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+)
+
+func TestPodDisruptionBudget(t *testing.T) {
+	labels := map[string]string{"service": ServiceName}
+
+	instance := testInstance()
+	if pdb := PodDisruptionBudget(instance, labels); pdb != nil {
+		t.Errorf("expected no PDB for a single replica, got %v", pdb)
+	}
+
+	instance.Spec.Replicas = 3
+	assertGolden(t, "pdb", PodDisruptionBudget(instance, labels))
+
+	// the HPA may scale up from a single replica
+	instance.Spec.Replicas = 1
+	instance.Spec.Autoscaling = octaviav1.OctaviaAutoscaling{Enabled: true, MinReplicas: 1, MaxReplicas: 3}
+	if pdb := PodDisruptionBudget(instance, labels); pdb == nil || pdb.Spec.MaxUnavailable.IntValue() != 1 {
+		t.Errorf("expected a PDB with one unavailable pod while autoscaling, got %v", pdb)
+	}
+
+	instance.Spec.Maintenance = true
+	if pdb := PodDisruptionBudget(instance, labels); pdb != nil {
+		t.Errorf("expected no PDB in maintenance mode, got %v", pdb)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"github.com/openstack-k8s-operators/lib-common/modules/common"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DBSyncComponent - component of the db-sync and db-restore job pods
+	DBSyncComponent = "db-sync"
+	// DBPurgeComponent - component of the db-purge job pods
+	DBPurgeComponent = "db-purge"
+	// DBBackupComponent - component of the db-backup job pods
+	DBBackupComponent = "db-backup"
+)
+
+// JobPodLabels - labels of the job pods of the component. They must not match the API service
+// selector and let the topology spread constraints select the pods of the component.
+func JobPodLabels(component string) map[string]string {
+	return map[string]string{
+		common.AppSelector: ServiceName + "-" + component,
+	}
+}
+
+// applyScheduling - applies the node selector, tolerations, topology spread constraints, priority
+// class and affinity override of the spec to the pod spec. Topology spread constraints without a
+// label selector get one which selects podLabels, if set.
+func applyScheduling(
+	instance *octaviav1.OctaviaAPI,
+	podSpec *corev1.PodSpec,
+	podLabels map[string]string,
+) {
+	if len(instance.Spec.NodeSelector) > 0 {
+		podSpec.NodeSelector = instance.Spec.NodeSelector
+	}
+	if len(instance.Spec.Tolerations) > 0 {
+		podSpec.Tolerations = instance.Spec.Tolerations
+	}
+	if instance.Spec.PriorityClassName != "" {
+		podSpec.PriorityClassName = instance.Spec.PriorityClassName
+	}
+	if instance.Spec.Affinity != nil {
+		podSpec.Affinity = instance.Spec.Affinity.DeepCopy()
+	}
+
+	for _, c := range instance.Spec.TopologySpreadConstraints {
+		constraint := *c.DeepCopy()
+		if constraint.LabelSelector == nil && len(podLabels) > 0 {
+			constraint.LabelSelector = &metav1.LabelSelector{
+				MatchLabels: podLabels,
+			}
+		}
+		podSpec.TopologySpreadConstraints = append(podSpec.TopologySpreadConstraints, constraint)
+	}
+}
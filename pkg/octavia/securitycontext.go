@@ -0,0 +1,70 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// OctaviaUID - uid of the octavia user in the kolla images
+	OctaviaUID int64 = 42437
+	// OctaviaGID - gid of the octavia group in the kolla images
+	OctaviaGID int64 = 42437
+)
+
+// getPodSecurityContext - pod security context which satisfies the restricted Pod Security Standard.
+// The fsGroup makes the emptyDir volumes writable for the octavia user.
+func getPodSecurityContext() *corev1.PodSecurityContext {
+	runAsUser := OctaviaUID
+	runAsGroup := OctaviaGID
+	fsGroup := OctaviaGID
+	runAsNonRoot := true
+
+	return &corev1.PodSecurityContext{
+		RunAsUser:    &runAsUser,
+		RunAsGroup:   &runAsGroup,
+		FSGroup:      &fsGroup,
+		RunAsNonRoot: &runAsNonRoot,
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
+
+// getContainerSecurityContext - container security context which satisfies the restricted
+// Pod Security Standard
+func getContainerSecurityContext() *corev1.SecurityContext {
+	runAsUser := OctaviaUID
+	runAsNonRoot := true
+	allowPrivilegeEscalation := false
+	readOnlyRootFilesystem := true
+
+	return &corev1.SecurityContext{
+		RunAsUser:                &runAsUser,
+		RunAsNonRoot:             &runAsNonRoot,
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		ReadOnlyRootFilesystem:   &readOnlyRootFilesystem,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{
+				"ALL",
+			},
+		},
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
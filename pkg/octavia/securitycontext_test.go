@@ -0,0 +1,95 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"testing"
+
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// assertRestricted - checks the fields of the pod spec the restricted Pod Security Standard
+// enforces, without the PodSecurity admission of an API server
+func assertRestricted(t *testing.T, podSpec corev1.PodSpec) {
+	t.Helper()
+
+	podSC := podSpec.SecurityContext
+	if podSC == nil || podSC.RunAsNonRoot == nil || !*podSC.RunAsNonRoot {
+		t.Errorf("pod does not run as non-root: %v", podSC)
+	} else if podSC.SeccompProfile == nil || podSC.SeccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault {
+		t.Errorf("pod has no RuntimeDefault seccomp profile: %v", podSC.SeccompProfile)
+	}
+	if podSpec.HostNetwork || podSpec.HostPID || podSpec.HostIPC {
+		t.Error("pod uses host namespaces")
+	}
+	for _, v := range podSpec.Volumes {
+		if v.HostPath != nil {
+			t.Errorf("volume %s is a hostPath", v.Name)
+		}
+	}
+
+	for _, c := range append(podSpec.InitContainers, podSpec.Containers...) {
+		sc := c.SecurityContext
+		if sc == nil {
+			t.Errorf("%s: no security context", c.Name)
+			continue
+		}
+		if sc.RunAsUser == nil || *sc.RunAsUser == 0 {
+			t.Errorf("%s: runs as root", c.Name)
+		}
+		if sc.Privileged != nil && *sc.Privileged {
+			t.Errorf("%s: privileged", c.Name)
+		}
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			t.Errorf("%s: allows privilege escalation", c.Name)
+		}
+		if sc.ReadOnlyRootFilesystem == nil || !*sc.ReadOnlyRootFilesystem {
+			t.Errorf("%s: writable root filesystem", c.Name)
+		}
+		if sc.Capabilities == nil || len(sc.Capabilities.Add) != 0 ||
+			len(sc.Capabilities.Drop) != 1 || sc.Capabilities.Drop[0] != "ALL" {
+			t.Errorf("%s: capabilities not limited: %v", c.Name, sc.Capabilities)
+		}
+		// without NET_BIND_SERVICE a non-root process can't bind privileged ports
+		for _, p := range c.Ports {
+			if p.ContainerPort < 1024 {
+				t.Errorf("%s: privileged port %d", c.Name, p.ContainerPort)
+			}
+		}
+	}
+}
+
+func TestPodSecurity(t *testing.T) {
+	instance := testInstance()
+	instance.Spec.EnabledProviders = append(instance.Spec.EnabledProviders, octaviav1.ProviderDriverOVN)
+	labels := map[string]string{"service": ServiceName}
+
+	t.Run("deployment", func(t *testing.T) {
+		assertRestricted(t, Deployment(instance, "confighash", labels).Spec.Template.Spec)
+	})
+	t.Run("dbsync job", func(t *testing.T) {
+		assertRestricted(t, DbSyncJob(instance, labels).Spec.Template.Spec)
+	})
+	t.Run("dbpurge cronjob", func(t *testing.T) {
+		assertRestricted(t, DbPurgeCronJob(instance, labels).Spec.JobTemplate.Spec.Template.Spec)
+	})
+	t.Run("db backup job", func(t *testing.T) {
+		backup := testBackup(octaviav1.OctaviaDBBackupTarget{PersistentVolumeClaim: "backup"})
+		assertRestricted(t, DBBackupJob(backup, instance, labels).Spec.Template.Spec)
+	})
+}
@@ -0,0 +1,158 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// EndpointName - name of the service and route of the endpoint
+func EndpointName(endpointType endpoint.Endpoint) string {
+	return ServiceName + "-" + string(endpointType)
+}
+
+// EndpointLabels - labels of the service and route of the endpoint
+func EndpointLabels(endpointType endpoint.Endpoint, selector map[string]string) map[string]string {
+	return util.MergeStringMaps(
+		selector,
+		map[string]string{
+			string(endpointType): "true",
+		},
+	)
+}
+
+// EndpointService - the service of the endpoint selecting the API pods, with the service
+// overrides of the spec applied
+func EndpointService(
+	instance *octaviav1.OctaviaAPI,
+	endpointType endpoint.Endpoint,
+	selector map[string]string,
+) *corev1.Service {
+	override := instance.GetServiceOverride(endpointType)
+	name := EndpointName(endpointType)
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.Namespace,
+			// the override labels can not replace the endpoint labels used to list the services
+			Labels:      util.MergeStringMaps(EndpointLabels(endpointType, selector), override.Labels),
+			Annotations: override.Annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: selector,
+			Ports: []corev1.ServicePort{
+				{
+					Name:     name,
+					Port:     GetEndpointPort(instance, endpointType),
+					Protocol: corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+	if override.Type != "" {
+		svc.Spec.Type = override.Type
+	}
+	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		svc.Spec.LoadBalancerIP = override.LoadBalancerIP
+	}
+	// single-stack services of the default family, if not set
+	svc.Spec.IPFamilyPolicy = override.IPFamilyPolicy
+	svc.Spec.IPFamilies = override.IPFamilies
+
+	return svc
+}
+
+// EndpointRoute - the route of the endpoint, with the custom hostname of the public route
+func EndpointRoute(
+	instance *octaviav1.OctaviaAPI,
+	endpointType endpoint.Endpoint,
+	selector map[string]string,
+) *routev1.Route {
+	name := EndpointName(endpointType)
+
+	route := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.Namespace,
+			Labels:    EndpointLabels(endpointType, selector),
+		},
+		Spec: routev1.RouteSpec{
+			To: routev1.RouteTargetReference{
+				Kind: "Service",
+				Name: name,
+			},
+			Port: &routev1.RoutePort{
+				TargetPort: intstr.FromString(name),
+			},
+		},
+	}
+	if endpointType == endpoint.EndpointPublic {
+		route.Spec.Host = instance.Spec.Override.PublicRoute.Host
+	}
+	// TLS gets terminated by the virtual host of the endpoint
+	if GetEndpointScheme(instance, endpointType) == "https" {
+		route.Spec.TLS = &routev1.TLSConfig{
+			Termination:                   routev1.TLSTerminationPassthrough,
+			InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+		}
+	}
+
+	return route
+}
+
+// ServiceEndpointURL - the URL of an endpoint exposed without route. Services of type LoadBalancer
+// are reached using the load balancer ingress address, returns an empty string if there is none yet.
+// Other services are reached using the service DNS name.
+func ServiceEndpointURL(svc *corev1.Service, scheme string) string {
+	port := strconv.Itoa(int(svc.Spec.Ports[0].Port))
+
+	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			// JoinHostPort encloses IPv6 addresses in brackets
+			if ingress.IP != "" {
+				return scheme + "://" + net.JoinHostPort(ingress.IP, port)
+			}
+			if ingress.Hostname != "" {
+				return scheme + "://" + net.JoinHostPort(ingress.Hostname, port)
+			}
+		}
+		return ""
+	}
+
+	return scheme + "://" + net.JoinHostPort(fmt.Sprintf("%s.%s.svc", svc.Name, svc.Namespace), port)
+}
+
+// RouteEndpointURL - the URL of an endpoint exposed using a route with the hostname host
+func RouteEndpointURL(host string, scheme string) string {
+	if strings.HasPrefix(host, "http") {
+		return host
+	}
+	return scheme + "://" + host
+}
@@ -0,0 +1,105 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"testing"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestEndpointServices(t *testing.T) {
+	labels := map[string]string{"service": ServiceName}
+	routeEnabled := false
+
+	instance := testInstance()
+	instance.Spec.Override = octaviav1.OctaviaOverride{
+		Service: map[endpoint.Endpoint]octaviav1.OctaviaServiceOverride{
+			endpoint.EndpointInternal: {
+				Type:           corev1.ServiceTypeLoadBalancer,
+				LoadBalancerIP: "172.17.0.80",
+				Annotations: map[string]string{
+					"metallb.universe.tf/address-pool": "internalapi",
+				},
+				Labels: map[string]string{
+					"network": "internalapi",
+				},
+			},
+		},
+		PublicRoute: octaviav1.OctaviaRouteOverride{
+			Enabled: &routeEnabled,
+			Host:    "octavia.example.com",
+		},
+	}
+
+	assertGolden(t, "service_admin", EndpointService(instance, endpoint.EndpointAdmin, labels))
+	assertGolden(t, "service_internal", EndpointService(instance, endpoint.EndpointInternal, labels))
+	assertGolden(t, "route_public", EndpointRoute(instance, endpoint.EndpointPublic, labels))
+
+	instance.Spec.Endpoints.Public = octaviav1.OctaviaAPIEndpoint{
+		Port:      13876,
+		TLSSecret: "octavia-public-tls",
+	}
+	dualStack := corev1.IPFamilyPolicyPreferDualStack
+	instance.Spec.Override.Service[endpoint.EndpointAdmin] = octaviav1.OctaviaServiceOverride{
+		IPFamilyPolicy: &dualStack,
+		IPFamilies:     []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+	}
+	assertGolden(t, "service_admin_dualstack", EndpointService(instance, endpoint.EndpointAdmin, labels))
+
+	assertGolden(t, "service_public_tls", EndpointService(instance, endpoint.EndpointPublic, labels))
+	assertGolden(t, "route_public_tls", EndpointRoute(instance, endpoint.EndpointPublic, labels))
+
+	for endpointType, expected := range map[endpoint.Endpoint]bool{
+		endpoint.EndpointAdmin:    true,
+		endpoint.EndpointInternal: false,
+		endpoint.EndpointPublic:   false,
+	} {
+		if actual := instance.HasRoute(endpointType); actual != expected {
+			t.Errorf("HasRoute(%s) = %v, expected %v", endpointType, actual, expected)
+		}
+	}
+}
+
+func TestServiceEndpointURL(t *testing.T) {
+	labels := map[string]string{"service": ServiceName}
+
+	instance := testInstance()
+	instance.Spec.Override.Service = map[endpoint.Endpoint]octaviav1.OctaviaServiceOverride{
+		endpoint.EndpointInternal: {Type: corev1.ServiceTypeLoadBalancer},
+	}
+
+	svc := EndpointService(instance, endpoint.EndpointAdmin, labels)
+	if url := ServiceEndpointURL(svc, "http"); url != "http://octavia-admin.openstack.svc:9876" {
+		t.Errorf("unexpected URL of the ClusterIP service: %s", url)
+	}
+
+	svc = EndpointService(instance, endpoint.EndpointInternal, labels)
+	if url := ServiceEndpointURL(svc, "http"); url != "" {
+		t.Errorf("expected no URL without load balancer ingress, got %s", url)
+	}
+	svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "172.17.0.80"}}
+	if url := ServiceEndpointURL(svc, "http"); url != "http://172.17.0.80:9876" {
+		t.Errorf("unexpected URL of the LoadBalancer service: %s", url)
+	}
+	svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "fd00:bbbb::80"}}
+	if url := ServiceEndpointURL(svc, "https"); url != "https://[fd00:bbbb::80]:9876" {
+		t.Errorf("unexpected URL of the IPv6 LoadBalancer service: %s", url)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// tlsMountPath - directory the TLS secrets of the virtual hosts get mounted to
+const tlsMountPath = "/etc/pki/tls/octavia"
+
+// Endpoints - the endpoints of the API, in the order they get exposed
+var Endpoints = []endpoint.Endpoint{
+	endpoint.EndpointAdmin,
+	endpoint.EndpointInternal,
+	endpoint.EndpointPublic,
+}
+
+// VirtualHost - httpd virtual host serving the endpoints with the same port
+type VirtualHost struct {
+	// Name - name of the first endpoint served by the virtual host
+	Name      string
+	Port      int32
+	Endpoints []string
+	// TLSSecret - secret holding the certificate of the virtual host, empty for plain http
+	TLSSecret   string
+	TLSCertFile string
+	TLSKeyFile  string
+	// ProxyHeaders - keep the X-Forwarded-* headers, false for a virtual host which only serves
+	// the internal endpoint, as its requests do not pass a proxy
+	ProxyHeaders bool
+}
+
+// GetEndpointPort - returns the port of the endpoint, by default the APIPort
+func GetEndpointPort(instance *octaviav1.OctaviaAPI, endpointType endpoint.Endpoint) int32 {
+	return defaultInt32(instance.GetEndpointSettings(endpointType).Port, GetAPIPort(instance))
+}
+
+// GetEndpointScheme - returns https if the virtual host of the endpoint terminates TLS
+func GetEndpointScheme(instance *octaviav1.OctaviaAPI, endpointType endpoint.Endpoint) string {
+	if instance.GetEndpointSettings(endpointType).TLSSecret != "" {
+		return "https"
+	}
+	return "http"
+}
+
+// GetVirtualHosts - returns one virtual host per distinct endpoint port. The TLS settings of the
+// first endpoint with the port are used, see ValidateEndpoints.
+func GetVirtualHosts(instance *octaviav1.OctaviaAPI) []VirtualHost {
+	vhosts := []VirtualHost{}
+	byPort := map[int32]int{}
+
+	for _, endpointType := range Endpoints {
+		port := GetEndpointPort(instance, endpointType)
+		idx, ok := byPort[port]
+		if !ok {
+			vhost := VirtualHost{
+				Name:      string(endpointType),
+				Port:      port,
+				TLSSecret: instance.GetEndpointSettings(endpointType).TLSSecret,
+			}
+			if vhost.TLSSecret != "" {
+				vhost.TLSCertFile = filepath.Join(tlsMountPath, vhost.Name, corev1.TLSCertKey)
+				vhost.TLSKeyFile = filepath.Join(tlsMountPath, vhost.Name, corev1.TLSPrivateKeyKey)
+			}
+			vhosts = append(vhosts, vhost)
+			idx = len(vhosts) - 1
+			byPort[port] = idx
+		}
+
+		vhosts[idx].Endpoints = append(vhosts[idx].Endpoints, string(endpointType))
+		if endpointType != endpoint.EndpointInternal {
+			vhosts[idx].ProxyHeaders = true
+		}
+	}
+
+	return vhosts
+}
+
+// ValidateEndpoints - returns an error if endpoints sharing a port have different TLS settings
+func ValidateEndpoints(instance *octaviav1.OctaviaAPI) error {
+	tlsSecrets := map[int32]string{}
+	for _, endpointType := range Endpoints {
+		port := GetEndpointPort(instance, endpointType)
+		tlsSecret := instance.GetEndpointSettings(endpointType).TLSSecret
+		if existing, ok := tlsSecrets[port]; ok && existing != tlsSecret {
+			return fmt.Errorf("endpoint %s uses port %d of another endpoint with different TLS settings", endpointType, port)
+		}
+		tlsSecrets[port] = tlsSecret
+	}
+	return nil
+}
+
+// getTLSVolumes - volumes of the TLS secrets of the virtual hosts
+func getTLSVolumes(instance *octaviav1.OctaviaAPI) []corev1.Volume {
+	var tlsAccessMode int32 = 0440
+
+	volumes := []corev1.Volume{}
+	for _, vhost := range GetVirtualHosts(instance) {
+		if vhost.TLSSecret == "" {
+			continue
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name: "tls-" + vhost.Name,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName:  vhost.TLSSecret,
+					DefaultMode: &tlsAccessMode,
+				},
+			},
+		})
+	}
+	return volumes
+}
+
+// getTLSVolumeMounts - VolumeMounts of the TLS secrets of the virtual hosts
+func getTLSVolumeMounts(instance *octaviav1.OctaviaAPI) []corev1.VolumeMount {
+	volumeMounts := []corev1.VolumeMount{}
+	for _, vhost := range GetVirtualHosts(instance) {
+		if vhost.TLSSecret == "" {
+			continue
+		}
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "tls-" + vhost.Name,
+			MountPath: filepath.Join(tlsMountPath, vhost.Name),
+			ReadOnly:  true,
+		})
+	}
+	return volumeMounts
+}
@@ -0,0 +1,87 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package octavia
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
+	octaviav1 "github.com/openstack-k8s-operators/octavia-operator/api/v1beta1"
+)
+
+func testEndpointsInstance() *octaviav1.OctaviaAPI {
+	instance := testInstance()
+	instance.Kind = "OctaviaAPI"
+	instance.Spec.Endpoints = octaviav1.OctaviaAPIEndpoints{
+		Internal: octaviav1.OctaviaAPIEndpoint{
+			Port: 9877,
+		},
+		Public: octaviav1.OctaviaAPIEndpoint{
+			Port:      13876,
+			TLSSecret: "octavia-public-tls",
+		},
+	}
+	return instance
+}
+
+func TestGetVirtualHosts(t *testing.T) {
+	instance := testInstance()
+	vhosts := GetVirtualHosts(instance)
+	if len(vhosts) != 1 || vhosts[0].Port != OctaviaDefaultPort || !vhosts[0].ProxyHeaders {
+		t.Errorf("expected a single virtual host for all endpoints, got %+v", vhosts)
+	}
+
+	instance = testEndpointsInstance()
+	vhosts = GetVirtualHosts(instance)
+	if len(vhosts) != 3 {
+		t.Fatalf("expected a virtual host per endpoint, got %+v", vhosts)
+	}
+	if vhosts[1].Name != "internal" || vhosts[1].ProxyHeaders {
+		t.Errorf("expected the internal virtual host to drop the proxy headers, got %+v", vhosts[1])
+	}
+	if vhosts[2].TLSCertFile != "/etc/pki/tls/octavia/public/tls.crt" {
+		t.Errorf("unexpected certificate of the public virtual host: %+v", vhosts[2])
+	}
+	if scheme := GetEndpointScheme(instance, endpoint.EndpointPublic); scheme != "https" {
+		t.Errorf("expected https for the public endpoint, got %s", scheme)
+	}
+
+	if err := ValidateEndpoints(instance); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	instance.Spec.Endpoints.Admin.Port = 13876
+	if err := ValidateEndpoints(instance); err == nil {
+		t.Errorf("expected an error for endpoints sharing a port with different TLS settings")
+	}
+}
+
+func TestHttpdConfig(t *testing.T) {
+	templates, err := filepath.Abs("../../templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("OPERATOR_TEMPLATES", templates)
+
+	instance := testEndpointsInstance()
+	cms := ServiceConfigTemplates(instance, map[string]string{}, GetServiceConfigParameters(instance))
+	data, err := RenderTemplate(cms[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertGolden(t, "httpd_endpoints", data["httpd.conf"])
+}
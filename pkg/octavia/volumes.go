@@ -19,12 +19,25 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
+// emptyDirVolumes - writable volumes, as all containers run with a read-only root filesystem.
+// Maps the volume name to the mount path.
+var emptyDirVolumes = []struct {
+	name      string
+	mountPath string
+}{
+	{name: "octavia-run", mountPath: "/run/octavia"},
+	{name: "octavia-log", mountPath: "/var/log/octavia"},
+	{name: "httpd-run", mountPath: "/run/httpd"},
+	{name: "httpd-log", mountPath: "/var/log/httpd"},
+	{name: "tmp", mountPath: "/tmp"},
+}
+
 // getVolumes - service volumes
 func getVolumes(name string) []corev1.Volume {
 	var scriptsVolumeDefaultMode int32 = 0755
 	var config0640AccessMode int32 = 0640
 
-	return []corev1.Volume{
+	volumes := []corev1.Volume{
 		{
 			Name: "scripts",
 			VolumeSource: corev1.VolumeSource{
@@ -54,6 +67,17 @@ func getVolumes(name string) []corev1.Volume {
 			},
 		},
 	}
+
+	for _, v := range emptyDirVolumes {
+		volumes = append(volumes, corev1.Volume{
+			Name: v.name,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{Medium: ""},
+			},
+		})
+	}
+
+	return volumes
 }
 
 // getInitVolumeMounts - general init task VolumeMounts
@@ -74,12 +98,18 @@ func getInitVolumeMounts() []corev1.VolumeMount {
 			MountPath: "/var/lib/config-data/merged",
 			ReadOnly:  false,
 		},
+		{
+			Name:      "tmp",
+			MountPath: "/tmp",
+			ReadOnly:  false,
+		},
 	}
 }
 
-// getVolumeMounts - general VolumeMounts
+// getVolumeMounts - general VolumeMounts. The merged config files get mounted to their
+// final location, as the containers can not copy them there without root privileges.
 func getVolumeMounts() []corev1.VolumeMount {
-	return []corev1.VolumeMount{
+	volumeMounts := []corev1.VolumeMount{
 		{
 			Name:      "scripts",
 			MountPath: "/usr/local/bin/container-scripts",
@@ -88,7 +118,39 @@ func getVolumeMounts() []corev1.VolumeMount {
 		{
 			Name:      "config-data-merged",
 			MountPath: "/var/lib/config-data/merged",
-			ReadOnly:  false,
+			ReadOnly:  true,
 		},
+		{
+			Name:      "config-data-merged",
+			MountPath: "/etc/octavia/octavia.conf",
+			SubPath:   "octavia.conf",
+			ReadOnly:  true,
+		},
+		{
+			Name:      "config-data-merged",
+			MountPath: "/etc/octavia/octavia.conf.d/custom.conf",
+			SubPath:   "custom.conf",
+			ReadOnly:  true,
+		},
+	}
+
+	for _, v := range emptyDirVolumes {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      v.name,
+			MountPath: v.mountPath,
+			ReadOnly:  false,
+		})
 	}
+
+	return volumeMounts
+}
+
+// getAPIVolumeMounts - VolumeMounts of the httpd serving the API
+func getAPIVolumeMounts() []corev1.VolumeMount {
+	return append(getVolumeMounts(), corev1.VolumeMount{
+		Name:      "config-data-merged",
+		MountPath: "/etc/httpd/conf/httpd.conf",
+		SubPath:   "httpd.conf",
+		ReadOnly:  true,
+	})
 }
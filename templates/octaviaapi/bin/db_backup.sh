@@ -0,0 +1,54 @@
+#!/bin//bash
+#
+# Copyright 2022 Red Hat Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may
+# not use this file except in compliance with the License. You may obtain
+# a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations
+# under the License.
+# tracing is disabled to not log the credentials
+set -e -o pipefail
+
+# Dumps the octavia DB into ${BackupDir}/${BackupFile}, uploads it to the object store if
+# configured, and removes all but the newest ${Retention} dumps.
+# The mysql client reads the password from MYSQL_PWD.
+
+DUMP=${BackupDir}/${BackupFile}
+
+mysqldump -h "${DatabaseHost}" -u "${DatabaseUser}" \
+    --single-transaction --routines --triggers "${DatabaseName}" | gzip > "${DUMP}.partial"
+mv "${DUMP}.partial" "${DUMP}"
+echo "Dumped ${DatabaseName} to ${DUMP}"
+
+if [ -z "${ObjectStoreURL}" ]; then
+    ls -1 ${BackupDir}/${DatabaseName}-*.sql.gz | sort -r | tail -n +$((Retention + 1)) | while read -r old; do
+        echo "Removing ${old}"
+        rm -f "${old}"
+    done
+    exit 0
+fi
+
+s3() {
+    curl --fail --silent --show-error --aws-sigv4 "aws:amz:${ObjectStoreRegion}:s3" \
+        --user "${ObjectStoreAccessKeyID}:${ObjectStoreSecretAccessKey}" "$@"
+}
+
+s3 --upload-file "${DUMP}" "${ObjectStoreURL}/${BackupFile}"
+rm -f "${DUMP}"
+echo "Uploaded ${BackupFile} to ${ObjectStoreURL}"
+
+s3 "${ObjectStoreURL}?list-type=2&prefix=${DatabaseName}-" | \
+    grep -o '<Key>[^<]*</Key>' | sed -e 's/<Key>//' -e 's/<\/Key>//' | \
+    grep '\.sql\.gz$' | sort -r | tail -n +$((Retention + 1)) | while read -r old; do
+    echo "Removing ${old}"
+    s3 -X DELETE "${ObjectStoreURL}/${old}"
+done
+
+exit 0
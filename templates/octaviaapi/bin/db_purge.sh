@@ -0,0 +1,39 @@
+#!/bin//bash
+#
+# Copyright 2022 Red Hat Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may
+# not use this file except in compliance with the License. You may obtain
+# a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations
+# under the License.
+set -ex
+
+# Purges the deleted load balancers and amphorae older than the age in days passed as
+# argument from the DB. octavia-db-manage has no purge command, the cleanup of the
+# housekeeping service gets run once instead.
+AGE_DAYS=${1:-30}
+AGE_SECONDS=$((AGE_DAYS * 86400))
+
+python3 - <<EOF_PURGE
+from oslo_config import cfg
+
+from octavia.common import service
+from octavia.controller.housekeeping import house_keeping
+
+service.prepare_service(['octavia-db-purge', '--config-file', '/etc/octavia/octavia.conf'])
+cfg.CONF.set_override('amphora_expiry_age', ${AGE_SECONDS}, group='house_keeping')
+cfg.CONF.set_override('load_balancer_expiry_age', ${AGE_SECONDS}, group='house_keeping')
+
+db_cleanup = house_keeping.DatabaseCleanup()
+db_cleanup.delete_old_amphorae()
+db_cleanup.cleanup_load_balancers()
+EOF_PURGE
+
+exit 0
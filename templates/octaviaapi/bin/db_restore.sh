@@ -0,0 +1,35 @@
+#!/bin//bash
+#
+# Copyright 2022 Red Hat Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may
+# not use this file except in compliance with the License. You may obtain
+# a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations
+# under the License.
+# tracing is disabled to not log the credentials
+set -e -o pipefail
+
+# Restores the octavia DB from the dump ${BackupDir}/${BackupFile}, downloaded from the
+# object store first if configured. The db-sync of the job upgrades the restored schema.
+# The mysql client reads the password from MYSQL_PWD.
+
+DUMP=${BackupDir}/${BackupFile}
+
+if [ -n "${ObjectStoreURL}" ]; then
+    curl --fail --silent --show-error --aws-sigv4 "aws:amz:${ObjectStoreRegion}:s3" \
+        --user "${ObjectStoreAccessKeyID}:${ObjectStoreSecretAccessKey}" \
+        --output "${DUMP}" "${ObjectStoreURL}/${BackupFile}"
+    echo "Downloaded ${BackupFile} from ${ObjectStoreURL}"
+fi
+
+gunzip -c "${DUMP}" | mysql -h "${DatabaseHost}" -u "${DatabaseUser}" "${DatabaseName}"
+echo "Restored ${DatabaseName} from ${DUMP}"
+
+exit 0
@@ -40,5 +40,19 @@ do
   merge_config_dir ${dir}
 done
 
+# IPv6 addresses need to be enclosed in brackets in the connection URL
+if [[ ${DBHOST} == *:* && ${DBHOST} != \[* ]]; then
+    DBHOST="[${DBHOST}]"
+fi
+
 # set secrets
 crudini --set ${SVC_CFG_MERGED} database connection mysql+pymysql://${DBUSER}:${DBPASSWORD}@${DBHOST}/${DB}
+
+# application credential of the service user, if managed by the operator. Tracing gets
+# disabled to not log the secret.
+if [ -n "${ApplicationCredentialID}" ]; then
+    set +x
+    crudini --set ${SVC_CFG_MERGED} service_auth application_credential_id ${ApplicationCredentialID}
+    crudini --set ${SVC_CFG_MERGED} service_auth application_credential_secret ${ApplicationCredentialSecret}
+    set -x
+fi